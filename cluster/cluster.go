@@ -1,9 +1,150 @@
 package cluster
 
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	DefaultConvergenceTolerance = 1e-5
+	DefaultMaxIterCount         = 300
+)
+
 type FuzzySuperCluster interface {
-	Adjust(iterCount uint) error
+	// Adjust clusters the points once per restart and keeps the best restart.
+	// It stops with the context error once ctx is done.
+	Adjust(ctx context.Context, opts *AdjustOptions) error
 	SilhouetteCoeff() float64
 	ClusteredPoints() []*FuzzyPoint
 	Centroids() []*FuzzyPoint
 	DimCount() (int, error)
 }
+
+// ProgressFunc observes the objective of a restart after each of its iterations.
+type ProgressFunc func(restartIdx, iteration int, objective float64)
+
+// AdjustOptions bounds and observes the restarts of a super cluster.
+type AdjustOptions struct {
+	RestartCount uint
+	// MaxIterCount caps the iterations of each restart. A restart stopped by it keeps its last partition.
+	MaxIterCount int
+	// Tolerance is the largest centroid shift for k-means and the largest membership degree change
+	// for fuzzy c-means at which a restart converges.
+	Tolerance float64
+	// Progress may be nil. Calls are serialized even when restarts run concurrently.
+	Progress ProgressFunc
+}
+
+func DefaultAdjustOptions(restartCount uint) *AdjustOptions {
+	return &AdjustOptions{
+		RestartCount: restartCount,
+		MaxIterCount: DefaultMaxIterCount,
+		Tolerance:    DefaultConvergenceTolerance,
+	}
+}
+
+func (o *AdjustOptions) validate() error {
+	if o.Tolerance <= 0 {
+		return fmt.Errorf("Convergence tolerance must be positive, got %f", o.Tolerance)
+	}
+
+	if o.MaxIterCount < 1 {
+		return fmt.Errorf("Max iteration count must be positive, got %d", o.MaxIterCount)
+	}
+
+	return nil
+}
+
+// report calls Progress if set.
+func (o *AdjustOptions) report(restartIdx, iteration int, objective func() float64) {
+	if o.Progress != nil {
+		o.Progress(restartIdx, iteration, objective())
+	}
+}
+
+// serialized returns a copy of the options whose Progress may be called from concurrent restarts.
+func (o *AdjustOptions) serialized() *AdjustOptions {
+	serializedOpts := *o
+
+	if o.Progress == nil {
+		return &serializedOpts
+	}
+
+	mu := sync.Mutex{}
+
+	serializedOpts.Progress = func(restartIdx, iteration int, objective float64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		o.Progress(restartIdx, iteration, objective)
+	}
+
+	return &serializedOpts
+}
+
+func dimCount(points []*FuzzyPoint) (int, error) {
+	if len(points) == 0 {
+		return 0, fmt.Errorf("No points to clusterize")
+	}
+
+	return points[0].DimCount(), nil
+}
+
+func clonePoints(points []*FuzzyPoint) []*FuzzyPoint {
+	clonedPoints := []*FuzzyPoint{}
+
+	for _, point := range points {
+		clonedPoint := point.Clone()
+		clonedPoints = append(clonedPoints, clonedPoint)
+	}
+
+	return clonedPoints
+}
+
+// silhouetteCoeff expects clustered points since only they know which cluster they belong to.
+func silhouetteCoeff(metric DistanceMetric, points []*FuzzyPoint, clusterCount int) float64 {
+	if clusterCount == 1 || len(points) == 0 {
+		return 0.0
+	}
+
+	cumSilhouetteCoeff := 0.0
+
+	for _, point := range points {
+		intraCumDist := 0.0
+		neighbourCumDist := 0.0
+		intraCnt := 0
+		neighbourCnt := 0
+
+		nearestNeighbour := point.nearestClusterIdx()
+
+		for _, otherPoint := range points {
+			if point == otherPoint {
+				continue
+			}
+
+			if otherPoint.BestFitClusterIdx == point.BestFitClusterIdx {
+				dist := metric.Dist(point, otherPoint)
+				intraCumDist += dist
+				intraCnt++
+			} else if otherPoint.BestFitClusterIdx == nearestNeighbour {
+				dist := metric.Dist(point, otherPoint)
+				neighbourCumDist += dist
+				neighbourCnt++
+			}
+		}
+
+		// Points alone in their cluster have a silhouette of 0 by convention.
+		if intraCnt == 0 || neighbourCnt == 0 {
+			continue
+		}
+
+		intraDistMean := intraCumDist / float64(intraCnt)
+		neighbourDistMean := neighbourCumDist / float64(neighbourCnt)
+
+		cumSilhouetteCoeff += (neighbourDistMean - intraDistMean) / math.Max(neighbourDistMean, intraDistMean)
+	}
+
+	return cumSilhouetteCoeff / float64(len(points))
+}
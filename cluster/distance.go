@@ -0,0 +1,382 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	EuclideanDistance = "euclidean"
+	// SquaredEuclideanDistance is already squared so objectives and indices built on squared distances use it as is.
+	SquaredEuclideanDistance = "squared-euclidean"
+	ManhattanDistance        = "manhattan"
+	ChebyshevDistance        = "chebyshev"
+	CosineDistance           = "cosine"
+	// StandardizedEuclideanDistance scales each axis by its standard deviation so no axis dominates.
+	StandardizedEuclideanDistance = "standardized-euclidean"
+	// MahalanobisDistance also accounts for the correlation between axes.
+	MahalanobisDistance = "mahalanobis"
+
+	// singularPivot is the largest pivot magnitude at which a covariance matrix is considered singular.
+	singularPivot = 1e-12
+)
+
+// DistanceMetric measures how far apart two points are.
+type DistanceMetric interface {
+	Dist(a, b *FuzzyPoint) float64
+	String() string
+}
+
+// squaredMetric is implemented by metrics whose Dist is already a squared distance.
+type squaredMetric interface {
+	squared()
+}
+
+// squaredDist squares a distance measured by metric unless the metric squared it already.
+func squaredDist(metric DistanceMetric, dist float64) float64 {
+	if _, ok := metric.(squaredMetric); ok {
+		return dist
+	}
+
+	return dist * dist
+}
+
+func DistanceMetricNames() []string {
+	return []string{
+		EuclideanDistance,
+		SquaredEuclideanDistance,
+		ManhattanDistance,
+		ChebyshevDistance,
+		CosineDistance,
+		StandardizedEuclideanDistance,
+		MahalanobisDistance,
+	}
+}
+
+// NewDistanceMetric returns the metric with the given name. Standardized Euclidean and Mahalanobis
+// metrics are fitted to the variances and covariances of points.
+func NewDistanceMetric(name string, points []*FuzzyPoint) (DistanceMetric, error) {
+	switch name {
+	case EuclideanDistance:
+		return &euclideanMetric{}, nil
+	case SquaredEuclideanDistance:
+		return &squaredEuclideanMetric{}, nil
+	case ManhattanDistance:
+		return &manhattanMetric{}, nil
+	case ChebyshevDistance:
+		return &chebyshevMetric{}, nil
+	case CosineDistance:
+		return &cosineMetric{}, nil
+	case StandardizedEuclideanDistance:
+		return newStandardizedEuclideanMetric(points)
+	case MahalanobisDistance:
+		return newMahalanobisMetric(points)
+	default:
+		return nil, fmt.Errorf("Invalid distance metric provided %s", name)
+	}
+}
+
+type euclideanMetric struct{}
+
+func (e *euclideanMetric) Dist(a, b *FuzzyPoint) float64 {
+	return a.Dist(b)
+}
+
+func (e *euclideanMetric) String() string {
+	return EuclideanDistance
+}
+
+type squaredEuclideanMetric struct{}
+
+func (s *squaredEuclideanMetric) Dist(a, b *FuzzyPoint) float64 {
+	return math.Pow(a.Dist(b), 2)
+}
+
+func (s *squaredEuclideanMetric) String() string {
+	return SquaredEuclideanDistance
+}
+
+func (s *squaredEuclideanMetric) squared() {}
+
+type manhattanMetric struct{}
+
+func (m *manhattanMetric) Dist(a, b *FuzzyPoint) float64 {
+	dist := 0.0
+
+	for dim, coord := range a.Coords {
+		dist += math.Abs(coord - b.Coords[dim])
+	}
+
+	return dist
+}
+
+func (m *manhattanMetric) String() string {
+	return ManhattanDistance
+}
+
+type chebyshevMetric struct{}
+
+func (c *chebyshevMetric) Dist(a, b *FuzzyPoint) float64 {
+	dist := 0.0
+
+	for dim, coord := range a.Coords {
+		dist = math.Max(dist, math.Abs(coord-b.Coords[dim]))
+	}
+
+	return dist
+}
+
+func (c *chebyshevMetric) String() string {
+	return ChebyshevDistance
+}
+
+// cosineMetric is one minus the cosine similarity. The origin is at distance 1 from every other point.
+type cosineMetric struct{}
+
+func (c *cosineMetric) Dist(a, b *FuzzyPoint) float64 {
+	dot := 0.0
+	aNorm := 0.0
+	bNorm := 0.0
+
+	for dim, coord := range a.Coords {
+		dot += coord * b.Coords[dim]
+		aNorm += coord * coord
+		bNorm += b.Coords[dim] * b.Coords[dim]
+	}
+
+	if aNorm == 0 && bNorm == 0 {
+		return 0.0
+	}
+
+	if aNorm == 0 || bNorm == 0 {
+		return 1.0
+	}
+
+	// Rounding may push the similarity slightly out of [-1, 1].
+	similarity := math.Max(-1, math.Min(1, dot/math.Sqrt(aNorm*bNorm)))
+
+	return 1 - similarity
+}
+
+func (c *cosineMetric) String() string {
+	return CosineDistance
+}
+
+type standardizedEuclideanMetric struct {
+	variances []float64
+}
+
+func newStandardizedEuclideanMetric(points []*FuzzyPoint) (DistanceMetric, error) {
+	covariance, err := covarianceMatrix(points)
+	if err != nil {
+		return nil, err
+	}
+
+	variances := make([]float64, len(covariance))
+
+	for dim := range covariance {
+		if covariance[dim][dim] <= 0 {
+			return nil, fmt.Errorf("Standardized euclidean distance needs positive variance, got %f on dim %d", covariance[dim][dim], dim)
+		}
+
+		variances[dim] = covariance[dim][dim]
+	}
+
+	return &standardizedEuclideanMetric{variances: variances}, nil
+}
+
+func (s *standardizedEuclideanMetric) Dist(a, b *FuzzyPoint) float64 {
+	dist := 0.0
+
+	for dim, coord := range a.Coords {
+		dist += math.Pow(coord-b.Coords[dim], 2) / s.variances[dim]
+	}
+
+	return math.Sqrt(dist)
+}
+
+func (s *standardizedEuclideanMetric) String() string {
+	return StandardizedEuclideanDistance
+}
+
+type mahalanobisMetric struct {
+	invCovariance [][]float64
+}
+
+func newMahalanobisMetric(points []*FuzzyPoint) (DistanceMetric, error) {
+	covariance, err := covarianceMatrix(points)
+	if err != nil {
+		return nil, err
+	}
+
+	invCovariance, err := invertMatrix(covariance)
+	if err != nil {
+		return nil, fmt.Errorf("Error inverting covariance matrix: %s", err)
+	}
+
+	return &mahalanobisMetric{invCovariance: invCovariance}, nil
+}
+
+func (m *mahalanobisMetric) Dist(a, b *FuzzyPoint) float64 {
+	diffs := make([]float64, len(a.Coords))
+
+	for dim, coord := range a.Coords {
+		diffs[dim] = coord - b.Coords[dim]
+	}
+
+	dist := 0.0
+
+	for i, row := range m.invCovariance {
+		for j, value := range row {
+			dist += diffs[i] * value * diffs[j]
+		}
+	}
+
+	// Rounding may yield tiny negative values for near-identical points.
+	return math.Sqrt(math.Max(dist, 0))
+}
+
+func (m *mahalanobisMetric) String() string {
+	return MahalanobisDistance
+}
+
+func covarianceMatrix(points []*FuzzyPoint) ([][]float64, error) {
+	dimCount, err := dimCount(points)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) < 2 {
+		return nil, fmt.Errorf("Covariance needs at least 2 points, got %d", len(points))
+	}
+
+	means := make([]float64, dimCount)
+
+	for _, point := range points {
+		for dim, coord := range point.Coords {
+			means[dim] += coord / float64(len(points))
+		}
+	}
+
+	covariance := make([][]float64, dimCount)
+
+	for i := range covariance {
+		covariance[i] = make([]float64, dimCount)
+	}
+
+	for _, point := range points {
+		for i := 0; i < dimCount; i++ {
+			for j := 0; j < dimCount; j++ {
+				covariance[i][j] += (point.Coords[i] - means[i]) * (point.Coords[j] - means[j])
+			}
+		}
+	}
+
+	for i := range covariance {
+		for j := range covariance[i] {
+			covariance[i][j] /= float64(len(points) - 1)
+		}
+	}
+
+	return covariance, nil
+}
+
+// invertMatrix uses Gauss-Jordan elimination with partial pivoting.
+func invertMatrix(matrix [][]float64) ([][]float64, error) {
+	size := len(matrix)
+	augmented := make([][]float64, size)
+
+	for i, row := range matrix {
+		augmented[i] = make([]float64, 2*size)
+		copy(augmented[i], row)
+		augmented[i][size+i] = 1
+	}
+
+	for col := 0; col < size; col++ {
+		pivotRow := col
+
+		for row := col + 1; row < size; row++ {
+			if math.Abs(augmented[row][col]) > math.Abs(augmented[pivotRow][col]) {
+				pivotRow = row
+			}
+		}
+
+		if math.Abs(augmented[pivotRow][col]) < singularPivot {
+			return nil, fmt.Errorf("Matrix is singular at column %d", col)
+		}
+
+		augmented[col], augmented[pivotRow] = augmented[pivotRow], augmented[col]
+		pivot := augmented[col][col]
+
+		for j := range augmented[col] {
+			augmented[col][j] /= pivot
+		}
+
+		for row := 0; row < size; row++ {
+			if row == col {
+				continue
+			}
+
+			factor := augmented[row][col]
+
+			for j := range augmented[row] {
+				augmented[row][j] -= factor * augmented[col][j]
+			}
+		}
+	}
+
+	inverse := make([][]float64, size)
+
+	for i, row := range augmented {
+		inverse[i] = row[size:]
+	}
+
+	return inverse, nil
+}
+
+// determinant uses Gaussian elimination with partial pivoting.
+func determinant(matrix [][]float64) (float64, error) {
+	size := len(matrix)
+	reduced := make([][]float64, size)
+
+	for i, row := range matrix {
+		if len(row) != size {
+			return 0, fmt.Errorf("Matrix isn't square")
+		}
+
+		reduced[i] = append([]float64{}, row...)
+	}
+
+	det := 1.0
+
+	for col := 0; col < size; col++ {
+		pivotRow := col
+
+		for row := col + 1; row < size; row++ {
+			if math.Abs(reduced[row][col]) > math.Abs(reduced[pivotRow][col]) {
+				pivotRow = row
+			}
+		}
+
+		if reduced[pivotRow][col] == 0 {
+			return 0, nil
+		}
+
+		if pivotRow != col {
+			reduced[col], reduced[pivotRow] = reduced[pivotRow], reduced[col]
+			det = -det
+		}
+
+		det *= reduced[col][col]
+
+		for row := col + 1; row < size; row++ {
+			factor := reduced[row][col] / reduced[col][col]
+
+			for j := col; j < size; j++ {
+				reduced[row][j] -= factor * reduced[col][j]
+			}
+		}
+	}
+
+	return det, nil
+}
@@ -0,0 +1,186 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+)
+
+const matrixTolerance = 1e-9
+
+func assertMatrix(t *testing.T, want, got [][]float64) {
+	t.Helper()
+
+	for i, row := range want {
+		for j, value := range row {
+			if math.Abs(got[i][j]-value) > matrixTolerance {
+				t.Fatalf("got matrix %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   float64
+	}{
+		{"2x2", [][]float64{{4, 7}, {2, 6}}, 10},
+		// The zero leading entry forces a row swap which flips the sign.
+		{"2x2 with pivoting", [][]float64{{0, 1}, {2, 3}}, -2},
+		{"3x3", [][]float64{{2, -3, 1}, {2, 0, -1}, {1, 4, 5}}, 49},
+		{"singular", [][]float64{{1, 2, 3}, {2, 4, 6}, {1, 0, 1}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := determinant(tt.matrix)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if math.Abs(got-tt.want) > matrixTolerance {
+				t.Errorf("got determinant %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeterminantRejectsNonSquareMatrix(t *testing.T) {
+	if _, err := determinant([][]float64{{1, 2}, {3, 4}, {5, 6}}); err == nil {
+		t.Error("expected an error for a non-square matrix")
+	}
+}
+
+func TestInvertMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		want   [][]float64
+	}{
+		{"2x2", [][]float64{{4, 7}, {2, 6}}, [][]float64{{0.6, -0.7}, {-0.2, 0.4}}},
+		{"3x3", [][]float64{{1, 2, 3}, {0, 1, 4}, {5, 6, 0}}, [][]float64{{-24, 18, 5}, {20, -15, -4}, {-5, 4, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := invertMatrix(tt.matrix)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertMatrix(t, tt.want, got)
+		})
+	}
+}
+
+func TestInvertMatrixRejectsSingularMatrix(t *testing.T) {
+	if _, err := invertMatrix([][]float64{{1, 2}, {2, 4}}); err == nil {
+		t.Error("expected an error for a singular matrix")
+	}
+}
+
+func TestCovarianceMatrix(t *testing.T) {
+	points := []*FuzzyPoint{
+		NewFuzzyPoint([]float64{0, 0}, ""),
+		NewFuzzyPoint([]float64{2, 1}, ""),
+		NewFuzzyPoint([]float64{4, 5}, ""),
+	}
+
+	got, err := covarianceMatrix(points)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Means 2 and 2 with the sample covariance dividing by 2.
+	assertMatrix(t, [][]float64{{4, 5}, {5, 7}}, got)
+}
+
+func TestCovarianceMatrixNeedsTwoPoints(t *testing.T) {
+	if _, err := covarianceMatrix([]*FuzzyPoint{NewFuzzyPoint([]float64{1, 2}, "")}); err == nil {
+		t.Error("expected an error for a single point")
+	}
+}
+
+func TestMahalanobisMetricRejectsSingularCovariance(t *testing.T) {
+	// Collinear points don't vary across the line they lie on.
+	points := []*FuzzyPoint{
+		NewFuzzyPoint([]float64{0, 0}, ""),
+		NewFuzzyPoint([]float64{1, 2}, ""),
+		NewFuzzyPoint([]float64{2, 4}, ""),
+	}
+
+	if _, err := NewDistanceMetric(MahalanobisDistance, points); err == nil {
+		t.Error("expected an error for a singular covariance matrix")
+	}
+}
+
+func TestMahalanobisMetricUndoesCovariance(t *testing.T) {
+	points := []*FuzzyPoint{
+		NewFuzzyPoint([]float64{0, 0}, ""),
+		NewFuzzyPoint([]float64{2, 1}, ""),
+		NewFuzzyPoint([]float64{4, 5}, ""),
+	}
+
+	metric, err := NewDistanceMetric(MahalanobisDistance, points)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The inverse of [[4, 5], [5, 7]] is [[7, -5], [-5, 4]] / 3 so a unit step on the first axis is at distance sqrt(7/3).
+	got := metric.Dist(NewFuzzyPoint([]float64{1, 0}, ""), NewFuzzyPoint([]float64{0, 0}, ""))
+
+	if math.Abs(got-math.Sqrt(7.0/3)) > matrixTolerance {
+		t.Errorf("got distance %f, want %f", got, math.Sqrt(7.0/3))
+	}
+}
+
+func TestSquaredEuclideanMatchesEuclideanWhereDistancesGetSquared(t *testing.T) {
+	points := []*FuzzyPoint{}
+
+	for _, coords := range [][]float64{{0, 0}, {1, 0}, {0, 1}, {5, 5}, {6, 5}, {5, 7}} {
+		points = append(points, NewFuzzyPoint(coords, ""))
+	}
+
+	centroids := []*FuzzyPoint{NewFuzzyPoint([]float64{0.5, 0.5}, ""), NewFuzzyPoint([]float64{5, 6}, "")}
+	centroids[0].BestFitClusterIdx = 0
+	centroids[1].BestFitClusterIdx = 1
+
+	euclidean, squared := &euclideanMetric{}, &squaredEuclideanMetric{}
+	euclideanPoints, squaredPoints := clonePoints(points), clonePoints(points)
+
+	for i := range points {
+		euclideanPoints[i].setMembershipDegree(euclidean, centroids)
+		squaredPoints[i].setMembershipDegree(squared, centroids)
+	}
+
+	euclideanFCM := &fuzzyCMeansSuperCluster{fuzzifier: DefaultFuzzifier, metric: euclidean}
+	squaredFCM := &fuzzyCMeansSuperCluster{fuzzifier: DefaultFuzzifier, metric: squared}
+	euclideanFCM.updateMembershipDegrees(euclideanPoints, centroids)
+	squaredFCM.updateMembershipDegrees(squaredPoints, centroids)
+
+	for i := range points {
+		for clusterIdx := range centroids {
+			if math.Abs(euclideanPoints[i].MembershipDegree(clusterIdx)-squaredPoints[i].MembershipDegree(clusterIdx)) > matrixTolerance {
+				t.Errorf("got membership degrees %f and %f of point %d", euclideanPoints[i].MembershipDegree(clusterIdx), squaredPoints[i].MembershipDegree(clusterIdx), i)
+			}
+		}
+
+		euclideanPoints[i].BestFitClusterIdx = euclideanPoints[i].mostProbableClusterIdx()
+	}
+
+	if math.Abs(euclideanFCM.objective(centroids, euclideanPoints)-squaredFCM.objective(centroids, euclideanPoints)) > matrixTolerance {
+		t.Error("got different fuzzy c-means objectives")
+	}
+
+	superCluster := &fuzzyCMeansSuperCluster{points: euclideanPoints, clusteredPoints: euclideanPoints, centroids: centroids}
+
+	for _, name := range []string{CalinskiHarabaszIndex, XieBeniIndex} {
+		euclideanIndex, _ := NewValidityIndex(name, euclidean)
+		squaredIndex, _ := NewValidityIndex(name, squared)
+
+		if math.Abs(euclideanIndex.Score(superCluster)-squaredIndex.Score(superCluster)) > matrixTolerance {
+			t.Errorf("got different %s scores", name)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// ReseedFarthestStrategy moves an empty centroid onto the point farthest from its own centroid.
+	ReseedFarthestStrategy = "reseed-farthest"
+	// SplitLargestStrategy splits the largest cluster in two along its dimension of largest spread.
+	SplitLargestStrategy = "split-largest"
+	// DropClusterStrategy removes an empty cluster so the restart ends up with fewer clusters.
+	DropClusterStrategy         = "drop"
+	DefaultEmptyClusterStrategy = ReseedFarthestStrategy
+)
+
+// EmptyClusterRecovery records a cluster which lost all its points during k-means and how it was recovered.
+type EmptyClusterRecovery struct {
+	Restart   int `json:"restart"`
+	Iteration int `json:"iteration"`
+	// ClusterIdx is the index the cluster had when it emptied.
+	ClusterIdx int `json:"clusterIdx"`
+	// Strategy may be DropClusterStrategy even when another strategy was configured
+	// since clusters of coincident points can't be reseeded or split.
+	Strategy string `json:"strategy"`
+}
+
+// EmptyClusterReporter is implemented by super clusters able to recover from empty clusters.
+type EmptyClusterReporter interface {
+	// EmptyClusterRecoveries returns the recoveries of the restart the super cluster kept.
+	EmptyClusterRecoveries() []*EmptyClusterRecovery
+}
+
+func EmptyClusterStrategies() []string {
+	return []string{ReseedFarthestStrategy, SplitLargestStrategy, DropClusterStrategy}
+}
+
+func validateEmptyClusterStrategy(strategy string) error {
+	for _, knownStrategy := range EmptyClusterStrategies() {
+		if strategy == knownStrategy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid empty cluster strategy %s", strategy)
+}
+
+// recoverEmptyClusters applies strategy to every empty cluster and returns the possibly reduced centroids and
+// cluster sizes together with the recoveries, which lack their restart and iteration.
+func recoverEmptyClusters(metric DistanceMetric, points, centroids []*FuzzyPoint, clusterSizes []int, strategy string) ([]*FuzzyPoint, []int, []*EmptyClusterRecovery) {
+	recoveries := []*EmptyClusterRecovery{}
+
+	// Iterating backwards keeps the indices of yet unvisited clusters stable when dropping.
+	for clusterIdx := len(centroids) - 1; clusterIdx >= 0; clusterIdx-- {
+		if clusterSizes[clusterIdx] > 0 {
+			continue
+		}
+
+		recovered := false
+
+		switch strategy {
+		case ReseedFarthestStrategy:
+			recovered = reseedFromFarthest(metric, points, centroids, clusterSizes, clusterIdx)
+		case SplitLargestStrategy:
+			recovered = splitLargest(points, centroids, clusterSizes, clusterIdx)
+		}
+
+		appliedStrategy := strategy
+
+		if !recovered {
+			centroids, clusterSizes = dropCluster(points, centroids, clusterSizes, clusterIdx)
+			appliedStrategy = DropClusterStrategy
+		}
+
+		recoveries = append(recoveries, &EmptyClusterRecovery{ClusterIdx: clusterIdx, Strategy: appliedStrategy})
+	}
+
+	return centroids, clusterSizes, recoveries
+}
+
+// reseedFromFarthest never takes the last point of a cluster so it can't empty another one.
+func reseedFromFarthest(metric DistanceMetric, points, centroids []*FuzzyPoint, clusterSizes []int, emptyIdx int) bool {
+	var farthestPoint *FuzzyPoint
+	maxDist := 0.0
+
+	for _, point := range points {
+		if !point.hasBestFitCluster() || clusterSizes[point.BestFitClusterIdx] < 2 {
+			continue
+		}
+
+		if dist := metric.Dist(point, centroids[point.BestFitClusterIdx]); dist > maxDist {
+			maxDist = dist
+			farthestPoint = point
+		}
+	}
+
+	if farthestPoint == nil {
+		return false
+	}
+
+	centroids[emptyIdx].Coords = append([]float64{}, farthestPoint.Coords...)
+	clusterSizes[farthestPoint.BestFitClusterIdx]--
+	clusterSizes[emptyIdx]++
+	farthestPoint.BestFitClusterIdx = emptyIdx
+
+	return true
+}
+
+// splitLargest moves the centroids of the largest and the empty cluster a standard deviation apart
+// along the dimension of largest spread and divides the points of the largest cluster between them.
+func splitLargest(points, centroids []*FuzzyPoint, clusterSizes []int, emptyIdx int) bool {
+	largestIdx := 0
+
+	for clusterIdx, size := range clusterSizes {
+		if size > clusterSizes[largestIdx] {
+			largestIdx = clusterIdx
+		}
+	}
+
+	if clusterSizes[largestIdx] < 2 {
+		return false
+	}
+
+	center := centroids[largestIdx].Coords
+	splitDim := 0
+	maxVariance := 0.0
+
+	for dim := range center {
+		variance := 0.0
+
+		for _, point := range points {
+			if point.BestFitClusterIdx == largestIdx {
+				variance += math.Pow(point.Coords[dim]-center[dim], 2)
+			}
+		}
+
+		variance /= float64(clusterSizes[largestIdx])
+
+		if variance > maxVariance {
+			maxVariance = variance
+			splitDim = dim
+		}
+	}
+
+	upperCount := 0
+
+	for _, point := range points {
+		if point.BestFitClusterIdx == largestIdx && point.Coords[splitDim] > center[splitDim] {
+			upperCount++
+		}
+	}
+
+	// Points exactly on the split stay in the largest cluster so a split without points above it can't help.
+	if maxVariance == 0 || upperCount == 0 {
+		return false
+	}
+
+	lowerCoords := append([]float64{}, center...)
+	upperCoords := append([]float64{}, center...)
+	lowerCoords[splitDim] -= math.Sqrt(maxVariance)
+	upperCoords[splitDim] += math.Sqrt(maxVariance)
+
+	centroids[largestIdx].Coords = lowerCoords
+	centroids[emptyIdx].Coords = upperCoords
+
+	for _, point := range points {
+		if point.BestFitClusterIdx == largestIdx && point.Coords[splitDim] > center[splitDim] {
+			point.BestFitClusterIdx = emptyIdx
+			clusterSizes[largestIdx]--
+			clusterSizes[emptyIdx]++
+		}
+	}
+
+	return true
+}
+
+// dropCluster removes the cluster and renumbers the ones after it.
+func dropCluster(points, centroids []*FuzzyPoint, clusterSizes []int, clusterIdx int) ([]*FuzzyPoint, []int) {
+	centroids = append(centroids[:clusterIdx], centroids[clusterIdx+1:]...)
+	clusterSizes = append(clusterSizes[:clusterIdx], clusterSizes[clusterIdx+1:]...)
+
+	for i := clusterIdx; i < len(centroids); i++ {
+		centroids[i].BestFitClusterIdx = i
+	}
+
+	for _, point := range points {
+		if point.BestFitClusterIdx > clusterIdx {
+			point.BestFitClusterIdx--
+		}
+	}
+
+	return centroids, clusterSizes
+}
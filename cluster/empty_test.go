@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// coincidentSeedClusters assigns points at 0, 1, 2, 10 and 11 to seeds at 1, 1 and 10.5.
+// Ties go to the first of the coincident seeds so cluster 1 ends up empty.
+func coincidentSeedClusters(t *testing.T, strategy string) ([]*FuzzyPoint, []*FuzzyPoint, []int) {
+	t.Helper()
+
+	points := []*FuzzyPoint{}
+
+	for _, coord := range []float64{0, 1, 2, 10, 11} {
+		points = append(points, NewFuzzyPoint([]float64{coord}, ""))
+	}
+
+	centroids := []*FuzzyPoint{}
+
+	for i, coord := range []float64{1, 1, 10.5} {
+		centroid := NewFuzzyPoint([]float64{coord}, "")
+		centroid.BestFitClusterIdx = i
+		centroids = append(centroids, centroid)
+	}
+
+	superCluster, err := NewKMeansSuperCluster(points, len(centroids), 1, strategy, &euclideanMetric{}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	clusterSizes := make([]int, len(centroids))
+	superCluster.(*kMeansSuperCluster).adjustClusters(points, centroids, clusterSizes)
+
+	if clusterSizes[1] != 0 {
+		t.Fatalf("got cluster sizes %v, want cluster 1 empty", clusterSizes)
+	}
+
+	return points, centroids, clusterSizes
+}
+
+func assertClusters(t *testing.T, points, centroids []*FuzzyPoint, clusterSizes []int, wantIndices, wantSizes []int) {
+	t.Helper()
+
+	for i, point := range points {
+		if point.BestFitClusterIdx != wantIndices[i] {
+			t.Errorf("got point %v in cluster %d, want %d", point.Coords, point.BestFitClusterIdx, wantIndices[i])
+		}
+	}
+
+	if len(clusterSizes) != len(wantSizes) || len(centroids) != len(wantSizes) {
+		t.Fatalf("got %d centroids of sizes %v, want sizes %v", len(centroids), clusterSizes, wantSizes)
+	}
+
+	for i, size := range clusterSizes {
+		if size != wantSizes[i] {
+			t.Errorf("got cluster sizes %v, want %v", clusterSizes, wantSizes)
+		}
+
+		if centroids[i].BestFitClusterIdx != i {
+			t.Errorf("got centroid %d numbered %d", i, centroids[i].BestFitClusterIdx)
+		}
+	}
+}
+
+func TestRecoverEmptyClusters(t *testing.T) {
+	tests := []struct {
+		strategy        string
+		wantIndices     []int
+		wantSizes       []int
+		wantCentroidsAt []float64
+		wantStrategy    string
+	}{
+		{
+			// The point at 0 is the first one farthest from its centroid.
+			strategy:        ReseedFarthestStrategy,
+			wantIndices:     []int{1, 0, 0, 2, 2},
+			wantSizes:       []int{2, 1, 2},
+			wantCentroidsAt: []float64{1, 0, 10.5},
+			wantStrategy:    ReseedFarthestStrategy,
+		},
+		{
+			// Cluster 0 has variance 2/3 around 1 and only the point at 2 lies above it.
+			strategy:        SplitLargestStrategy,
+			wantIndices:     []int{0, 0, 1, 2, 2},
+			wantSizes:       []int{2, 1, 2},
+			wantCentroidsAt: []float64{1 - math.Sqrt(2.0/3), 1 + math.Sqrt(2.0/3), 10.5},
+			wantStrategy:    SplitLargestStrategy,
+		},
+		{
+			strategy:        DropClusterStrategy,
+			wantIndices:     []int{0, 0, 0, 1, 1},
+			wantSizes:       []int{3, 2},
+			wantCentroidsAt: []float64{1, 10.5},
+			wantStrategy:    DropClusterStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			points, centroids, clusterSizes := coincidentSeedClusters(t, tt.strategy)
+
+			centroids, clusterSizes, recoveries := recoverEmptyClusters(&euclideanMetric{}, points, centroids, clusterSizes, tt.strategy)
+
+			assertClusters(t, points, centroids, clusterSizes, tt.wantIndices, tt.wantSizes)
+
+			for i, centroid := range centroids {
+				if math.Abs(centroid.Coords[0]-tt.wantCentroidsAt[i]) > 1e-9 {
+					t.Errorf("got centroid %d at %f, want %f", i, centroid.Coords[0], tt.wantCentroidsAt[i])
+				}
+			}
+
+			if len(recoveries) != 1 || recoveries[0].ClusterIdx != 1 || recoveries[0].Strategy != tt.wantStrategy {
+				t.Errorf("got recoveries %v, want cluster 1 recovered by %s", recoveries, tt.wantStrategy)
+			}
+		})
+	}
+}
+
+func TestRecoverEmptyClustersDropsClustersOfCoincidentPoints(t *testing.T) {
+	points := []*FuzzyPoint{}
+
+	for i := 0; i < 3; i++ {
+		point := NewFuzzyPoint([]float64{1}, "")
+		point.BestFitClusterIdx = 0
+		points = append(points, point)
+	}
+
+	centroids := []*FuzzyPoint{NewFuzzyPoint([]float64{1}, ""), NewFuzzyPoint([]float64{1}, "")}
+	centroids[0].BestFitClusterIdx = 0
+	centroids[1].BestFitClusterIdx = 1
+
+	for _, strategy := range []string{ReseedFarthestStrategy, SplitLargestStrategy} {
+		t.Run(strategy, func(t *testing.T) {
+			clonedPoints := clonePoints(points)
+			clonedCentroids := clonePoints(centroids)
+
+			clonedCentroids, clusterSizes, recoveries := recoverEmptyClusters(&euclideanMetric{}, clonedPoints, clonedCentroids, []int{3, 0}, strategy)
+
+			assertClusters(t, clonedPoints, clonedCentroids, clusterSizes, []int{0, 0, 0}, []int{3})
+
+			if len(recoveries) != 1 || recoveries[0].Strategy != DropClusterStrategy {
+				t.Errorf("got recoveries %v, want a single drop", recoveries)
+			}
+		})
+	}
+}
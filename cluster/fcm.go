@@ -0,0 +1,212 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+)
+
+const DefaultFuzzifier = 2.0
+
+// fuzzyCMeansSuperCluster lets membership degrees drive the centroid updates instead of hard assignments.
+type fuzzyCMeansSuperCluster struct {
+	points          []*FuzzyPoint
+	clusteredPoints []*FuzzyPoint
+	centroids       []*FuzzyPoint
+	clusterCount    int
+	fuzzifier       float64
+	metric          DistanceMetric
+	minObjective    float64
+	rnd             *rand.Rand
+}
+
+func NewFuzzyCMeansSuperCluster(points []*FuzzyPoint, clusterCount int, fuzzifier float64, metric DistanceMetric, rnd *rand.Rand) (FuzzySuperCluster, error) {
+	if fuzzifier <= 1 {
+		return nil, fmt.Errorf("Fuzzifier must be greater than 1, got %f", fuzzifier)
+	}
+
+	return &fuzzyCMeansSuperCluster{
+		points:          points,
+		clusteredPoints: []*FuzzyPoint{},
+		centroids:       []*FuzzyPoint{},
+		clusterCount:    clusterCount,
+		fuzzifier:       fuzzifier,
+		metric:          metric,
+		minObjective:    math.Inf(0),
+		rnd:             rnd,
+	}, nil
+}
+
+func (f *fuzzyCMeansSuperCluster) Adjust(ctx context.Context, opts *AdjustOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	for i := 0; i < int(opts.RestartCount); i++ {
+		clonedPoints := clonePoints(f.points)
+		centroids, err := f.clusterize(ctx, clonedPoints, i, opts)
+
+		if err != nil {
+			return fmt.Errorf("Error adjusting super cluster: %w", err)
+		}
+
+		objective := f.objective(centroids, clonedPoints)
+
+		if objective < f.minObjective {
+			log.Printf("Encountered a better fuzzy partition with objective %f", objective)
+
+			f.minObjective = objective
+			f.clusteredPoints = clonedPoints
+			f.centroids = centroids
+
+			alignCentroidActivities(f.centroids, f.clusteredPoints)
+		}
+	}
+
+	return nil
+}
+
+func (f *fuzzyCMeansSuperCluster) SilhouetteCoeff() float64 {
+	return silhouetteCoeff(f.metric, f.clusteredPoints, f.clusterCount)
+}
+
+func (f *fuzzyCMeansSuperCluster) ClusteredPoints() []*FuzzyPoint {
+	return f.clusteredPoints
+}
+
+func (f *fuzzyCMeansSuperCluster) Centroids() []*FuzzyPoint {
+	return f.centroids
+}
+
+func (f *fuzzyCMeansSuperCluster) DimCount() (int, error) {
+	return dimCount(f.points)
+}
+
+func (f *fuzzyCMeansSuperCluster) clusterize(ctx context.Context, points []*FuzzyPoint, restartIdx int, opts *AdjustOptions) ([]*FuzzyPoint, error) {
+	centroids, err := initialCentroids(f.metric, points, f.clusterCount, f.rnd)
+	if err != nil {
+		return nil, fmt.Errorf("Error building cluster: %s", err.Error())
+	}
+
+	f.updateMembershipDegrees(points, centroids)
+
+	converged := false
+
+	for iter := 0; iter < opts.MaxIterCount && !converged; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		f.updateCentroids(points, centroids)
+		converged = f.updateMembershipDegrees(points, centroids) < opts.Tolerance
+
+		opts.report(restartIdx, iter, func() float64 { return f.objective(centroids, points) })
+	}
+
+	if !converged {
+		log.Printf("Fuzzy c-means restart %d stopped at max iteration count %d before converging", restartIdx, opts.MaxIterCount)
+	}
+
+	for _, point := range points {
+		point.BestFitClusterIdx = point.mostProbableClusterIdx()
+	}
+
+	return centroids, nil
+}
+
+func (f *fuzzyCMeansSuperCluster) updateMembershipDegrees(points, centroids []*FuzzyPoint) float64 {
+	return updateFuzzyMembershipDegrees(points, centroids, f.fuzzifier, func(point *FuzzyPoint, centroidIdx int) float64 {
+		return squaredDist(f.metric, f.metric.Dist(point, centroids[centroidIdx]))
+	})
+}
+
+func (f *fuzzyCMeansSuperCluster) updateCentroids(points, centroids []*FuzzyPoint) {
+	updateFuzzyCentroids(points, centroids, f.fuzzifier)
+}
+
+// updateFuzzyMembershipDegrees returns the largest change of any membership degree.
+// squaredDist returns the squared distance of a point from the centroid with the given index.
+func updateFuzzyMembershipDegrees(points, centroids []*FuzzyPoint, fuzzifier float64, squaredDist func(point *FuzzyPoint, centroidIdx int) float64) float64 {
+	maxChange := 0.0
+	// Squared distances halve the usual exponent of 2 / (m - 1).
+	exp := 1 / (fuzzifier - 1)
+
+	for _, point := range points {
+		dists := make([]float64, len(centroids))
+		coincidentIdx := NoCluster
+
+		for i := range centroids {
+			dists[i] = squaredDist(point, i)
+
+			if dists[i] == 0 {
+				coincidentIdx = i
+			}
+		}
+
+		for i, centroid := range centroids {
+			membershipDegree := 0.0
+
+			if coincidentIdx != NoCluster {
+				if i == coincidentIdx {
+					membershipDegree = 1.0
+				}
+			} else {
+				cumRatio := 0.0
+
+				for _, otherDist := range dists {
+					cumRatio += math.Pow(dists[i]/otherDist, exp)
+				}
+
+				membershipDegree = 1 / cumRatio
+			}
+
+			clusterIdx := centroid.BestFitClusterIdx
+			maxChange = math.Max(maxChange, math.Abs(membershipDegree-point.membershipDegrees[clusterIdx]))
+			point.membershipDegrees[clusterIdx] = membershipDegree
+		}
+	}
+
+	return maxChange
+}
+
+func updateFuzzyCentroids(points, centroids []*FuzzyPoint, fuzzifier float64) {
+	for _, centroid := range centroids {
+		coords := make([]float64, len(centroid.Coords))
+		cumWeight := 0.0
+
+		for _, point := range points {
+			weight := math.Pow(point.MembershipDegree(centroid.BestFitClusterIdx), fuzzifier)
+
+			for dim, coord := range point.Coords {
+				coords[dim] += weight * coord
+			}
+
+			cumWeight += weight
+		}
+
+		if cumWeight == 0 {
+			continue
+		}
+
+		for dim := range coords {
+			coords[dim] /= cumWeight
+		}
+
+		centroid.Coords = coords
+	}
+}
+
+func (f *fuzzyCMeansSuperCluster) objective(centroids, points []*FuzzyPoint) float64 {
+	objective := 0.0
+
+	for _, centroid := range centroids {
+		for _, point := range points {
+			weight := math.Pow(point.MembershipDegree(centroid.BestFitClusterIdx), f.fuzzifier)
+			objective += weight * squaredDist(f.metric, f.metric.Dist(point, centroid))
+		}
+	}
+
+	return objective
+}
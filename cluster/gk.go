@@ -0,0 +1,267 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+)
+
+// CovarianceRidge is added to the diagonal of each fuzzy covariance matrix relative to its mean variance
+// so clusters flattened onto a subspace stay invertible.
+const CovarianceRidge = 1e-6
+
+// CovarianceSuperCluster is implemented by super clusters adapting a covariance matrix to each cluster.
+type CovarianceSuperCluster interface {
+	FuzzySuperCluster
+	// Covariance returns the fuzzy covariance matrix of the cluster with the given index.
+	Covariance(clusterIdx int) [][]float64
+}
+
+// gustafsonKesselSuperCluster is a fuzzy c-means whose clusters measure distance by their own
+// volume-normalized covariance so they can stretch into ellipsoids along correlated axes.
+type gustafsonKesselSuperCluster struct {
+	points          []*FuzzyPoint
+	clusteredPoints []*FuzzyPoint
+	centroids       []*FuzzyPoint
+	covariances     [][][]float64
+	clusterCount    int
+	fuzzifier       float64
+	// metric only seeds the clusters and measures validity indices. Clustering uses the cluster covariances.
+	metric       DistanceMetric
+	minObjective float64
+	rnd          *rand.Rand
+}
+
+func NewGustafsonKesselSuperCluster(points []*FuzzyPoint, clusterCount int, fuzzifier float64, metric DistanceMetric, rnd *rand.Rand) (CovarianceSuperCluster, error) {
+	if fuzzifier <= 1 {
+		return nil, fmt.Errorf("Fuzzifier must be greater than 1, got %f", fuzzifier)
+	}
+
+	return &gustafsonKesselSuperCluster{
+		points:          points,
+		clusteredPoints: []*FuzzyPoint{},
+		centroids:       []*FuzzyPoint{},
+		covariances:     [][][]float64{},
+		clusterCount:    clusterCount,
+		fuzzifier:       fuzzifier,
+		metric:          metric,
+		minObjective:    math.Inf(0),
+		rnd:             rnd,
+	}, nil
+}
+
+func (g *gustafsonKesselSuperCluster) Adjust(ctx context.Context, opts *AdjustOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	for i := 0; i < int(opts.RestartCount); i++ {
+		clonedPoints := clonePoints(g.points)
+		centroids, norms, err := g.clusterize(ctx, clonedPoints, i, opts)
+
+		if err != nil {
+			return fmt.Errorf("Error adjusting super cluster: %w", err)
+		}
+
+		objective := g.objective(centroids, norms, clonedPoints)
+
+		if objective < g.minObjective {
+			log.Printf("Encountered a better ellipsoidal partition with objective %f", objective)
+
+			g.minObjective = objective
+			g.clusteredPoints = clonedPoints
+			g.centroids = centroids
+			g.covariances = make([][][]float64, len(norms))
+
+			for clusterIdx, norm := range norms {
+				g.covariances[clusterIdx] = norm.covariance
+			}
+
+			alignCentroidActivities(g.centroids, g.clusteredPoints)
+		}
+	}
+
+	return nil
+}
+
+func (g *gustafsonKesselSuperCluster) SilhouetteCoeff() float64 {
+	return silhouetteCoeff(g.metric, g.clusteredPoints, g.clusterCount)
+}
+
+func (g *gustafsonKesselSuperCluster) ClusteredPoints() []*FuzzyPoint {
+	return g.clusteredPoints
+}
+
+func (g *gustafsonKesselSuperCluster) Centroids() []*FuzzyPoint {
+	return g.centroids
+}
+
+func (g *gustafsonKesselSuperCluster) DimCount() (int, error) {
+	return dimCount(g.points)
+}
+
+func (g *gustafsonKesselSuperCluster) Covariance(clusterIdx int) [][]float64 {
+	return g.covariances[clusterIdx]
+}
+
+// clusterNorm holds the fuzzy covariance of a cluster and the matrix inducing its distance norm.
+type clusterNorm struct {
+	covariance [][]float64
+	normMatrix [][]float64
+}
+
+// dist is the squared distance of point to centroid under the norm of the cluster.
+func (c *clusterNorm) dist(point, centroid *FuzzyPoint) float64 {
+	diffs := make([]float64, len(point.Coords))
+
+	for dim, coord := range point.Coords {
+		diffs[dim] = coord - centroid.Coords[dim]
+	}
+
+	dist := 0.0
+
+	for i, row := range c.normMatrix {
+		for j, value := range row {
+			dist += diffs[i] * value * diffs[j]
+		}
+	}
+
+	return math.Max(dist, 0)
+}
+
+func (g *gustafsonKesselSuperCluster) clusterize(ctx context.Context, points []*FuzzyPoint, restartIdx int, opts *AdjustOptions) ([]*FuzzyPoint, []*clusterNorm, error) {
+	centroids, err := initialCentroids(g.metric, points, g.clusterCount, g.rnd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error building cluster: %s", err.Error())
+	}
+
+	// Memberships are seeded by the metric since no cluster has a covariance yet.
+	updateFuzzyMembershipDegrees(points, centroids, g.fuzzifier, func(point *FuzzyPoint, centroidIdx int) float64 {
+		return squaredDist(g.metric, g.metric.Dist(point, centroids[centroidIdx]))
+	})
+
+	var norms []*clusterNorm
+	converged := false
+
+	for iter := 0; iter < opts.MaxIterCount && !converged; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		updateFuzzyCentroids(points, centroids, g.fuzzifier)
+
+		if norms, err = g.clusterNorms(points, centroids); err != nil {
+			return nil, nil, fmt.Errorf("Error building cluster: %s", err)
+		}
+
+		maxChange := updateFuzzyMembershipDegrees(points, centroids, g.fuzzifier, func(point *FuzzyPoint, centroidIdx int) float64 {
+			return norms[centroidIdx].dist(point, centroids[centroidIdx])
+		})
+		converged = maxChange < opts.Tolerance
+
+		opts.report(restartIdx, iter, func() float64 { return g.objective(centroids, norms, points) })
+	}
+
+	if !converged {
+		log.Printf("Gustafson-Kessel restart %d stopped at max iteration count %d before converging", restartIdx, opts.MaxIterCount)
+	}
+
+	for _, point := range points {
+		point.BestFitClusterIdx = point.mostProbableClusterIdx()
+	}
+
+	return centroids, norms, nil
+}
+
+// clusterNorms scales the inverse fuzzy covariance of each cluster to unit determinant so all clusters keep the same volume.
+func (g *gustafsonKesselSuperCluster) clusterNorms(points, centroids []*FuzzyPoint) ([]*clusterNorm, error) {
+	norms := make([]*clusterNorm, len(centroids))
+
+	for i, centroid := range centroids {
+		covariance := g.fuzzyCovariance(points, centroid)
+
+		det, err := determinant(covariance)
+		if err != nil {
+			return nil, err
+		}
+
+		if det <= 0 {
+			return nil, fmt.Errorf("Covariance of cluster %d isn't positive definite", centroid.BestFitClusterIdx)
+		}
+
+		invCovariance, err := invertMatrix(covariance)
+		if err != nil {
+			return nil, fmt.Errorf("Error inverting covariance of cluster %d: %s", centroid.BestFitClusterIdx, err)
+		}
+
+		scale := math.Pow(det, 1/float64(len(covariance)))
+
+		for _, row := range invCovariance {
+			for j := range row {
+				row[j] *= scale
+			}
+		}
+
+		norms[i] = &clusterNorm{covariance: covariance, normMatrix: invCovariance}
+	}
+
+	return norms, nil
+}
+
+func (g *gustafsonKesselSuperCluster) fuzzyCovariance(points []*FuzzyPoint, centroid *FuzzyPoint) [][]float64 {
+	dimCount := len(centroid.Coords)
+	covariance := make([][]float64, dimCount)
+
+	for i := range covariance {
+		covariance[i] = make([]float64, dimCount)
+	}
+
+	cumWeight := 0.0
+
+	for _, point := range points {
+		weight := math.Pow(point.MembershipDegree(centroid.BestFitClusterIdx), g.fuzzifier)
+		cumWeight += weight
+
+		for i := 0; i < dimCount; i++ {
+			for j := 0; j < dimCount; j++ {
+				covariance[i][j] += weight * (point.Coords[i] - centroid.Coords[i]) * (point.Coords[j] - centroid.Coords[j])
+			}
+		}
+	}
+
+	trace := 0.0
+
+	for i := range covariance {
+		for j := range covariance[i] {
+			if cumWeight > 0 {
+				covariance[i][j] /= cumWeight
+			}
+		}
+
+		trace += covariance[i][i]
+	}
+
+	// A cluster without spread still gets a tiny spherical covariance.
+	ridge := math.Max(CovarianceRidge*trace/float64(dimCount), CovarianceRidge)
+
+	for i := range covariance {
+		covariance[i][i] += ridge
+	}
+
+	return covariance
+}
+
+func (g *gustafsonKesselSuperCluster) objective(centroids []*FuzzyPoint, norms []*clusterNorm, points []*FuzzyPoint) float64 {
+	objective := 0.0
+
+	for i, centroid := range centroids {
+		for _, point := range points {
+			weight := math.Pow(point.MembershipDegree(centroid.BestFitClusterIdx), g.fuzzifier)
+			objective += weight * norms[i].dist(point, centroid)
+		}
+	}
+
+	return objective
+}
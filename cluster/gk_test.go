@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// elongatedBlob scatters points along the diagonal through center with a little noise across it.
+func elongatedBlob(center []float64, activity string, rnd *rand.Rand) []*FuzzyPoint {
+	points := []*FuzzyPoint{}
+
+	for t := -4.0; t <= 4; t += 0.5 {
+		noise := 0.2 * rnd.NormFloat64()
+		coords := []float64{center[0] + t + noise, center[1] + t - noise}
+		points = append(points, NewFuzzyPoint(coords, activity))
+	}
+
+	return points
+}
+
+func newTestGustafsonKessel(t *testing.T, points []*FuzzyPoint, clusterCount int) *gustafsonKesselSuperCluster {
+	t.Helper()
+
+	superCluster, err := NewGustafsonKesselSuperCluster(points, clusterCount, DefaultFuzzifier, &euclideanMetric{}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return superCluster.(*gustafsonKesselSuperCluster)
+}
+
+func TestClusterNormsHaveUnitDeterminant(t *testing.T) {
+	points := elongatedBlob([]float64{1, 2}, "", rand.New(rand.NewSource(1)))
+	centroid := NewFuzzyPoint([]float64{1, 2}, "")
+	centroid.BestFitClusterIdx = 0
+
+	for i, point := range points {
+		point.SetMembershipDegree(0, 0.2+0.6*float64(i%2))
+	}
+
+	g := newTestGustafsonKessel(t, points, 1)
+
+	norms, err := g.clusterNorms(points, []*FuzzyPoint{centroid})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	det, err := determinant(norms[0].normMatrix)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if math.Abs(det-1) > 1e-6 {
+		t.Errorf("got norm matrix determinant %f, want 1", det)
+	}
+
+	// The blob stretches along the diagonal so both axes vary alike and covary strongly.
+	covariance := norms[0].covariance
+
+	if covariance[0][1] <= 0 || covariance[0][1] < 0.9*math.Sqrt(covariance[0][0]*covariance[1][1]) {
+		t.Errorf("got covariance %v, want a strong positive correlation", covariance)
+	}
+}
+
+func TestFuzzyCovarianceOfCoincidentPointsIsInvertible(t *testing.T) {
+	points := []*FuzzyPoint{NewFuzzyPoint([]float64{1, 1}, ""), NewFuzzyPoint([]float64{1, 1}, "")}
+	centroid := NewFuzzyPoint([]float64{1, 1}, "")
+	centroid.BestFitClusterIdx = 0
+
+	for _, point := range points {
+		point.SetMembershipDegree(0, 1)
+	}
+
+	covariance := newTestGustafsonKessel(t, points, 1).fuzzyCovariance(points, centroid)
+
+	if _, err := invertMatrix(covariance); err != nil {
+		t.Errorf("got singular covariance %v: %s", covariance, err)
+	}
+}
+
+func TestGustafsonKesselSeparatesElongatedBlobs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	// The parallel blobs lie closer across than along their length so fuzzy c-means mixes them up.
+	points := append(elongatedBlob([]float64{0, 0}, "lower", rnd), elongatedBlob([]float64{0, 4}, "upper", rnd)...)
+
+	g := newTestGustafsonKessel(t, points, 2)
+
+	if err := g.Adjust(context.Background(), DefaultAdjustOptions(5)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	activityClusters := make(map[string]int)
+
+	for _, point := range g.ClusteredPoints() {
+		clusterIdx, ok := activityClusters[point.Activity]
+
+		if !ok {
+			activityClusters[point.Activity] = point.BestFitClusterIdx
+		} else if clusterIdx != point.BestFitClusterIdx {
+			t.Fatalf("points of blob %s ended up in clusters %d and %d", point.Activity, clusterIdx, point.BestFitClusterIdx)
+		}
+	}
+
+	if activityClusters["lower"] == activityClusters["upper"] {
+		t.Errorf("both blobs ended up in cluster %d", activityClusters["lower"])
+	}
+}
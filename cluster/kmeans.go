@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
@@ -18,45 +19,90 @@ type kMeansSuperCluster struct {
 	centroids       []*FuzzyPoint
 	clusterCount    int
 	minClusterDist  float64
+	rnd             *rand.Rand
+	// parallelism bounds the restarts running at once. Values below 1 mean GOMAXPROCS.
+	parallelism          int
+	emptyClusterStrategy string
+	metric               DistanceMetric
+	recoveries           []*EmptyClusterRecovery
 }
 
-func NewKMeansSuperCluster(points []*FuzzyPoint, clusterCount int) FuzzySuperCluster {
-	return &kMeansSuperCluster{
-		points:          points,
-		clusteredPoints: []*FuzzyPoint{},
-		centroids:       []*FuzzyPoint{},
-		clusterCount:    clusterCount,
-		minClusterDist:  math.Inf(0),
+// restartResult is the outcome of a single k-means restart.
+type restartResult struct {
+	clusteredPoints []*FuzzyPoint
+	centroids       []*FuzzyPoint
+	dist            float64
+	recoveries      []*EmptyClusterRecovery
+}
+
+func NewKMeansSuperCluster(points []*FuzzyPoint, clusterCount int, parallelism int, emptyClusterStrategy string, metric DistanceMetric, rnd *rand.Rand) (FuzzySuperCluster, error) {
+	if err := validateEmptyClusterStrategy(emptyClusterStrategy); err != nil {
+		return nil, err
 	}
+
+	return &kMeansSuperCluster{
+		points:               points,
+		clusteredPoints:      []*FuzzyPoint{},
+		centroids:            []*FuzzyPoint{},
+		clusterCount:         clusterCount,
+		minClusterDist:       math.Inf(0),
+		rnd:                  rnd,
+		parallelism:          parallelism,
+		emptyClusterStrategy: emptyClusterStrategy,
+		metric:               metric,
+		recoveries:           []*EmptyClusterRecovery{},
+	}, nil
 }
 
-func (k *kMeansSuperCluster) Adjust(iterCount uint) error {
-	for i := 0; i < int(iterCount); i++ {
+func (k *kMeansSuperCluster) Adjust(ctx context.Context, opts *AdjustOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	opts = opts.serialized()
+	results := make([]*restartResult, opts.RestartCount)
+
+	errs := runRestarts(ctx, int(opts.RestartCount), k.parallelism, k.rnd, func(restartIdx int, rnd *rand.Rand) error {
 		// Cloning points to keep original ones intact
-		clonedPoints := k.clonePoints()
-		centroids, err := k.clusterize(clonedPoints)
+		clonedPoints := clonePoints(k.points)
+		centroids, recoveries, err := k.clusterize(ctx, clonedPoints, restartIdx, rnd, opts)
 
 		if err != nil {
-			return fmt.Errorf("Error adjusting super cluster: %s", err.Error())
+			return err
 		}
 
-		dist := overallClusterDist(centroids, clonedPoints)
+		results[restartIdx] = &restartResult{
+			clusteredPoints: clonedPoints,
+			centroids:       centroids,
+			dist:            overallClusterDist(k.metric, centroids, clonedPoints),
+			recoveries:      recoveries,
+		}
 
-		if dist < k.minClusterDist {
-			log.Printf("Encounetered a better cluster with overall intra dist %f", dist)
+		return nil
+	})
 
-			k.minClusterDist = dist
-			k.clusteredPoints = clonedPoints
-			k.centroids = centroids
+	// Restarts are compared in order so ties go to the earliest one whatever order they completed in.
+	for restartIdx, result := range results {
+		if errs[restartIdx] != nil {
+			return fmt.Errorf("Error adjusting super cluster: %w", errs[restartIdx])
+		}
 
-			for _, point := range k.clusteredPoints {
-				point.setMembershipDegree(centroids)
-			}
+		if result.dist < k.minClusterDist {
+			log.Printf("Encounetered a better cluster with overall intra dist %f", result.dist)
 
-			k.alignCentroidActivities()
+			k.minClusterDist = result.dist
+			k.clusteredPoints = result.clusteredPoints
+			k.centroids = result.centroids
+			k.recoveries = result.recoveries
 		}
 	}
 
+	for _, point := range k.clusteredPoints {
+		point.setMembershipDegree(k.metric, k.centroids)
+	}
+
+	alignCentroidActivities(k.centroids, k.clusteredPoints)
+
 	return nil
 }
 
@@ -68,132 +114,73 @@ func (k *kMeansSuperCluster) Centroids() []*FuzzyPoint {
 	return k.centroids
 }
 
-// TODO: Remove dead code.
+// SilhouetteCoeff counts only the centroids kept since empty clusters may have been dropped.
 func (k *kMeansSuperCluster) SilhouetteCoeff() float64 {
-	if k.clusterCount == 1 {
-		return 0.0
-	}
-
-	cumSilhouetteCoeff := 0.0
-
-	for _, point := range k.points {
-		intraCumDist := 0.0
-		neighbourCumDist := 0.0
-		intraCnt := 0
-		neighbourCnt := 0
-
-		nearestNeighbour := point.nearestClusterIdx()
-
-		for _, otherPoint := range k.points {
-			if point == otherPoint {
-				continue
-			}
-
-			if otherPoint.BestFitClusterIdx == point.BestFitClusterIdx {
-				dist := point.Dist(otherPoint)
-				intraCumDist += dist
-				intraCnt++
-			} else if otherPoint.BestFitClusterIdx == nearestNeighbour {
-				dist := point.Dist(otherPoint)
-				neighbourCumDist += dist
-				neighbourCnt++
-			}
-		}
-
-		intraDistMean := intraCumDist / float64(intraCnt)
-		neighbourDistMean := neighbourCumDist / float64(neighbourCnt)
-
-		cumSilhouetteCoeff += (neighbourDistMean - intraDistMean) / math.Max(neighbourDistMean, intraDistMean)
-	}
-
-	return cumSilhouetteCoeff / float64(len(k.points))
+	return silhouetteCoeff(k.metric, k.clusteredPoints, len(k.centroids))
 }
 
-func (k *kMeansSuperCluster) DimCount() (int, error) {
-	if len(k.points) == 0 {
-		return 0, fmt.Errorf("No points to clusterize")
-	}
-
-	point := k.points[0]
-	return point.DimCount(), nil
+func (k *kMeansSuperCluster) EmptyClusterRecoveries() []*EmptyClusterRecovery {
+	return k.recoveries
 }
 
-func (k *kMeansSuperCluster) clonePoints() []*FuzzyPoint {
-	clonedPoints := []*FuzzyPoint{}
-
-	for _, point := range k.points {
-		clonedPoint := point.Clone()
-		clonedPoints = append(clonedPoints, clonedPoint)
-	}
-
-	return clonedPoints
+func (k *kMeansSuperCluster) DimCount() (int, error) {
+	return dimCount(k.points)
 }
 
-func (k *kMeansSuperCluster) clusterize(points []*FuzzyPoint) ([]*FuzzyPoint, error) {
-	centroids, err := k.initialCentroids(points)
+func (k *kMeansSuperCluster) clusterize(ctx context.Context, points []*FuzzyPoint, restartIdx int, rnd *rand.Rand, opts *AdjustOptions) ([]*FuzzyPoint, []*EmptyClusterRecovery, error) {
+	centroids, err := initialCentroids(k.metric, points, k.clusterCount, rnd)
 
 	if err != nil {
-		return nil, fmt.Errorf("Error building cluster: %s", err.Error())
+		return nil, nil, fmt.Errorf("Error building cluster: %s", err.Error())
 	}
 
 	clusterSizes := []int{}
+	recoveries := []*EmptyClusterRecovery{}
 
 	for i := 0; i < k.clusterCount; i++ {
 		centroids[i].BestFitClusterIdx = i
 		clusterSizes = append(clusterSizes, 0)
 	}
 
-	madeAdjustments := true
-
-	for madeAdjustments {
-		madeAdjustments = k.adjustClusters(points, centroids, clusterSizes)
-
-		if err := k.adjustCentroids(points, centroids, clusterSizes); err != nil {
-			return nil, fmt.Errorf("Eror building cluster: %s", err.Error())
+	for iter := 0; iter < opts.MaxIterCount; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
 		}
-	}
 
-	return centroids, nil
-}
+		madeAdjustments := k.adjustClusters(points, centroids, clusterSizes)
 
-func (k *kMeansSuperCluster) initialCentroids(points []*FuzzyPoint) ([]*FuzzyPoint, error) {
-	centroids := []*FuzzyPoint{}
+		var iterRecoveries []*EmptyClusterRecovery
+		centroids, clusterSizes, iterRecoveries = recoverEmptyClusters(k.metric, points, centroids, clusterSizes, k.emptyClusterStrategy)
 
-	probabilities := []float64{}
-	probSum := 0.0
+		for _, recovery := range iterRecoveries {
+			log.Printf("K-means restart %d recovered empty cluster %d at iteration %d by %s", restartIdx, recovery.ClusterIdx, iter, recovery.Strategy)
 
-	for range points {
-		probabilities = append(probabilities, InitialCentroidProb)
-		probSum += InitialCentroidProb
-	}
+			recovery.Restart = restartIdx
+			recovery.Iteration = iter
+			recoveries = append(recoveries, recovery)
+		}
 
-	for i := 0; i < k.clusterCount; i++ {
-		index, err := randDistributionIndex(probabilities, probSum, 0)
+		maxShift, err := k.adjustCentroids(points, centroids, clusterSizes)
 		if err != nil {
-			return nil, fmt.Errorf("Error selecting centroid %d: %s", i, err.Error())
+			return nil, nil, fmt.Errorf("Eror building cluster: %s", err.Error())
 		}
 
-		centroid := points[index].Clone()
-		centroid.BestFitClusterIdx = i
-
-		centroids = append(centroids, centroid)
+		opts.report(restartIdx, iter, func() float64 { return overallClusterDist(k.metric, centroids, points) })
 
-		probSum = 0.0
-
-		for j, point := range points {
-			_, minDist := bestFitCluster(centroids, point)
-
-			probabilities[j] = math.Pow(minDist, 2)
-			probSum += probabilities[j]
+		// A recovered cluster needs at least one more assignment before the restart can converge.
+		if len(iterRecoveries) == 0 && (!madeAdjustments || maxShift < opts.Tolerance) {
+			return centroids, recoveries, nil
 		}
 	}
 
-	return centroids, nil
+	log.Printf("K-means restart %d stopped at max iteration count %d before converging", restartIdx, opts.MaxIterCount)
+
+	return centroids, recoveries, nil
 }
 
 func (k *kMeansSuperCluster) adjustClusters(points, centroids []*FuzzyPoint, clusterSizes []int) (madeAdjustments bool) {
 	for _, point := range points {
-		bestFitClusterIdx, _ := bestFitCluster(centroids, point)
+		bestFitClusterIdx, _ := bestFitCluster(k.metric, centroids, point)
 
 		if bestFitClusterIdx != point.BestFitClusterIdx {
 			clusterSizes[bestFitClusterIdx]++
@@ -211,7 +198,10 @@ func (k *kMeansSuperCluster) adjustClusters(points, centroids []*FuzzyPoint, clu
 	return madeAdjustments
 }
 
-func (k *kMeansSuperCluster) adjustCentroids(points, centroids []*FuzzyPoint, clusterSizes []int) error {
+// adjustCentroids moves each centroid to the center of its cluster and returns the largest move.
+func (k *kMeansSuperCluster) adjustCentroids(points, centroids []*FuzzyPoint, clusterSizes []int) (float64, error) {
+	maxShift := 0.0
+
 	for i, centroid := range centroids {
 		if clusterSizes[i] == 0 {
 			continue
@@ -219,13 +209,14 @@ func (k *kMeansSuperCluster) adjustCentroids(points, centroids []*FuzzyPoint, cl
 
 		center, err := k.clusterCenter(points, i)
 		if err != nil {
-			return fmt.Errorf("Error adjusting cluster centers: %s", err.Error())
+			return 0.0, fmt.Errorf("Error adjusting cluster centers: %s", err.Error())
 		}
 
+		maxShift = math.Max(maxShift, k.metric.Dist(centroid, &FuzzyPoint{Coords: center}))
 		centroid.Coords = center
 	}
 
-	return nil
+	return maxShift, nil
 }
 
 func (k *kMeansSuperCluster) clusterCenter(points []*FuzzyPoint, clusterIdx int) ([]float64, error) {
@@ -271,16 +262,17 @@ func (k *kMeansSuperCluster) copyPoints(otherPoints []*FuzzyPoint) {
 	}
 }
 
-func (k *kMeansSuperCluster) alignCentroidActivities() {
-	for _, centroid := range k.centroids {
-		k.alignCentroidActivity(centroid)
+// alignCentroidActivities labels each centroid with the majority activity of its cluster.
+func alignCentroidActivities(centroids, points []*FuzzyPoint) {
+	for _, centroid := range centroids {
+		alignCentroidActivity(centroid, points)
 	}
 }
 
-func (k *kMeansSuperCluster) alignCentroidActivity(centroid *FuzzyPoint) {
+func alignCentroidActivity(centroid *FuzzyPoint, points []*FuzzyPoint) {
 	activityCounts := make(map[string]int)
 
-	for _, point := range k.clusteredPoints {
+	for _, point := range points {
 		if point.BestFitClusterIdx != centroid.BestFitClusterIdx {
 			continue
 		}
@@ -295,41 +287,42 @@ func (k *kMeansSuperCluster) alignCentroidActivity(centroid *FuzzyPoint) {
 	maxCnt := 0
 
 	for activity, cnt := range activityCounts {
-		if cnt > maxCnt {
+		// Ties are broken by name to stay independent of map ordering.
+		if cnt > maxCnt || (cnt == maxCnt && activity < centroid.Activity) {
 			maxCnt = cnt
 			centroid.Activity = activity
 		}
 	}
 }
 
-func overallClusterDist(centroids, points []*FuzzyPoint) float64 {
+func overallClusterDist(metric DistanceMetric, centroids, points []*FuzzyPoint) float64 {
 	overallDist := 0.0
 
 	for _, centroid := range centroids {
-		overallDist += clusterDist(centroid, points)
+		overallDist += clusterDist(metric, centroid, points)
 	}
 
 	return overallDist
 }
 
-func clusterDist(centroid *FuzzyPoint, points []*FuzzyPoint) float64 {
+func clusterDist(metric DistanceMetric, centroid *FuzzyPoint, points []*FuzzyPoint) float64 {
 	dist := 0.0
 
 	for _, point := range points {
 		if point.BestFitClusterIdx == centroid.BestFitClusterIdx {
-			dist += point.Dist(centroid)
+			dist += metric.Dist(point, centroid)
 		}
 	}
 
 	return dist
 }
 
-func bestFitCluster(centroids []*FuzzyPoint, point *FuzzyPoint) (int, float64) {
+func bestFitCluster(metric DistanceMetric, centroids []*FuzzyPoint, point *FuzzyPoint) (int, float64) {
 	bestFitClusterIdx := point.BestFitClusterIdx
 	minDist := math.Inf(0)
 
 	for clusterIdx, centroid := range centroids {
-		dist := point.Dist(centroid)
+		dist := metric.Dist(point, centroid)
 
 		if dist < minDist {
 			minDist = dist
@@ -340,12 +333,48 @@ func bestFitCluster(centroids []*FuzzyPoint, point *FuzzyPoint) (int, float64) {
 	return bestFitClusterIdx, minDist
 }
 
-func randDistributionIndex(probabilities []float64, probSum float64, attempts int) (int, error) {
+// initialCentroids picks centroids with kMeans++ seeding.
+func initialCentroids(metric DistanceMetric, points []*FuzzyPoint, clusterCount int, rnd *rand.Rand) ([]*FuzzyPoint, error) {
+	centroids := []*FuzzyPoint{}
+
+	probabilities := []float64{}
+	probSum := 0.0
+
+	for range points {
+		probabilities = append(probabilities, InitialCentroidProb)
+		probSum += InitialCentroidProb
+	}
+
+	for i := 0; i < clusterCount; i++ {
+		index, err := randDistributionIndex(rnd, probabilities, probSum, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Error selecting centroid %d: %s", i, err.Error())
+		}
+
+		centroid := points[index].Clone()
+		centroid.BestFitClusterIdx = i
+
+		centroids = append(centroids, centroid)
+
+		probSum = 0.0
+
+		for j, point := range points {
+			_, minDist := bestFitCluster(metric, centroids, point)
+
+			probabilities[j] = squaredDist(metric, minDist)
+			probSum += probabilities[j]
+		}
+	}
+
+	return centroids, nil
+}
+
+func randDistributionIndex(rnd *rand.Rand, probabilities []float64, probSum float64, attempts int) (int, error) {
 	if attempts > MaxCentroidSamplingAttempts {
 		return -1, fmt.Errorf("Random distribution index not selected")
 	}
 
-	randNum := rand.Float64() * probSum
+	randNum := rnd.Float64() * probSum
 	sum := 0.0
 
 	for i := 0; i < len(probabilities); i++ {
@@ -356,5 +385,5 @@ func randDistributionIndex(probabilities []float64, probSum float64, attempts in
 		}
 	}
 
-	return randDistributionIndex(probabilities, probSum, attempts+1)
+	return randDistributionIndex(rnd, probabilities, probSum, attempts+1)
 }
@@ -8,6 +8,8 @@ type FuzzyPoint struct {
 	BestFitClusterIdx int
 	Coords            []float64
 	Activity          string
+	// Group identifies the subject the point was recorded from. It is empty when unknown.
+	Group string
 	// Don't need to know how many clusters there are.
 	membershipDegrees map[int]float64
 }
@@ -32,6 +34,7 @@ func (f *FuzzyPoint) Clone() *FuzzyPoint {
 		BestFitClusterIdx: f.BestFitClusterIdx,
 		Coords:            f.Coords,
 		Activity:          f.Activity,
+		Group:             f.Group,
 		membershipDegrees: cloneMembershipDegrees,
 	}
 }
@@ -56,15 +59,20 @@ func (f *FuzzyPoint) MembershipDegree(clusterIdx int) float64 {
 	return f.membershipDegrees[clusterIdx]
 }
 
-func (f *FuzzyPoint) setMembershipDegree(centroids []*FuzzyPoint) {
+// SetMembershipDegree sets the membership degree of the point in a single cluster, leaving the others as they are.
+func (f *FuzzyPoint) SetMembershipDegree(clusterIdx int, membershipDegree float64) {
+	f.membershipDegrees[clusterIdx] = membershipDegree
+}
+
+func (f *FuzzyPoint) setMembershipDegree(metric DistanceMetric, centroids []*FuzzyPoint) {
 	totalMembership := 0.0
 
 	for _, centroid := range centroids {
-		dist := f.Dist(centroid)
+		dist := metric.Dist(f, centroid)
 		if dist == 0 {
 			f.membershipDegrees[centroid.BestFitClusterIdx] = 1
 		} else {
-			f.membershipDegrees[centroid.BestFitClusterIdx] = 1 / (dist * dist)
+			f.membershipDegrees[centroid.BestFitClusterIdx] = 1 / squaredDist(metric, dist)
 		}
 
 		totalMembership += f.membershipDegrees[centroid.BestFitClusterIdx]
@@ -83,12 +91,28 @@ func (f *FuzzyPoint) copy(other *FuzzyPoint) {
 	f.BestFitClusterIdx = other.BestFitClusterIdx
 	f.Coords = other.Coords
 	f.Activity = other.Activity
+	f.Group = other.Group
 
 	for i := 0; i < len(f.membershipDegrees); i++ {
 		f.membershipDegrees[i] = other.membershipDegrees[i]
 	}
 }
 
+func (f *FuzzyPoint) mostProbableClusterIdx() int {
+	bestClr := NoCluster
+	maxMembershipDegree := -1.0
+
+	for clusterIdx, membershipDegree := range f.membershipDegrees {
+		// Ties are broken by the lower index to stay independent of map ordering.
+		if membershipDegree > maxMembershipDegree || (membershipDegree == maxMembershipDegree && clusterIdx < bestClr) {
+			bestClr = clusterIdx
+			maxMembershipDegree = membershipDegree
+		}
+	}
+
+	return bestClr
+}
+
 func (f *FuzzyPoint) nearestClusterIdx() int {
 	nearestClr := NoCluster
 	maxMembershipDegree := 0.0
@@ -98,7 +122,7 @@ func (f *FuzzyPoint) nearestClusterIdx() int {
 			continue
 		}
 
-		if maxMembershipDegree < membershipDegree {
+		if maxMembershipDegree < membershipDegree || (maxMembershipDegree == membershipDegree && clusterIdx < nearestClr) {
 			nearestClr = clusterIdx
 			maxMembershipDegree = membershipDegree
 		}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// DefaultParallelism runs as many restarts at once as GOMAXPROCS allows.
+const DefaultParallelism = 0
+
+// restartFunc runs a single clustering restart with its own random source.
+type restartFunc func(restartIdx int, rnd *rand.Rand) error
+
+// runRestarts runs restartCount restarts on a pool of at most parallelism workers.
+// Seeds are drawn from rnd upfront so each restart sees the same random numbers whatever the parallelism.
+// Restarts not started before ctx is done fail with the context error.
+// It returns the errors of the restarts indexed by restart.
+func runRestarts(ctx context.Context, restartCount, parallelism int, rnd *rand.Rand, restart restartFunc) []error {
+	seeds := make([]int64, restartCount)
+
+	for i := range seeds {
+		seeds[i] = rnd.Int63()
+	}
+
+	if parallelism < 1 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	errs := make([]error, restartCount)
+	restartIdxs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for worker := 0; worker < parallelism && worker < restartCount; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for restartIdx := range restartIdxs {
+				if err := ctx.Err(); err != nil {
+					errs[restartIdx] = err
+					continue
+				}
+
+				errs[restartIdx] = restart(restartIdx, rand.New(rand.NewSource(seeds[restartIdx])))
+			}
+		}()
+	}
+
+	for restartIdx := 0; restartIdx < restartCount; restartIdx++ {
+		restartIdxs <- restartIdx
+	}
+
+	close(restartIdxs)
+	wg.Wait()
+
+	return errs
+}
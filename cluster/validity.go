@@ -0,0 +1,236 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	SilhouetteIndex       = "silhouette"
+	DaviesBouldinIndex    = "davies-bouldin"
+	CalinskiHarabaszIndex = "calinski-harabasz"
+	XieBeniIndex          = "xie-beni"
+	// XieBeniFuzzifier is the exponent applied to membership degrees by the Xie-Beni index.
+	XieBeniFuzzifier = 2.0
+)
+
+// ValidityIndex scores how well a super cluster partitions its points.
+type ValidityIndex interface {
+	Score(superCluster FuzzySuperCluster) float64
+	// Better tells whether score a is preferable to score b.
+	Better(a, b float64) bool
+	String() string
+}
+
+func ValidityIndexNames() []string {
+	return []string{SilhouetteIndex, DaviesBouldinIndex, CalinskiHarabaszIndex, XieBeniIndex}
+}
+
+// NewValidityIndex returns the index with the given name measuring distances by metric.
+// It should be the metric of the scored super clusters so clustering and scoring share a geometry.
+// The silhouette index relies on the super cluster for that.
+func NewValidityIndex(name string, metric DistanceMetric) (ValidityIndex, error) {
+	switch name {
+	case SilhouetteIndex:
+		return &silhouetteIndex{}, nil
+	case DaviesBouldinIndex:
+		return &daviesBouldinIndex{metric: metric}, nil
+	case CalinskiHarabaszIndex:
+		return &calinskiHarabaszIndex{metric: metric}, nil
+	case XieBeniIndex:
+		return &xieBeniIndex{metric: metric}, nil
+	default:
+		return nil, fmt.Errorf("Invalid validity index provided %s", name)
+	}
+}
+
+type silhouetteIndex struct{}
+
+func (s *silhouetteIndex) Score(superCluster FuzzySuperCluster) float64 {
+	return superCluster.SilhouetteCoeff()
+}
+
+func (s *silhouetteIndex) Better(a, b float64) bool {
+	return a > b
+}
+
+func (s *silhouetteIndex) String() string {
+	return SilhouetteIndex
+}
+
+type daviesBouldinIndex struct {
+	metric DistanceMetric
+}
+
+func (d *daviesBouldinIndex) Score(superCluster FuzzySuperCluster) float64 {
+	centroids := nonEmptyCentroids(superCluster)
+	if len(centroids) < 2 {
+		return math.NaN()
+	}
+
+	scatters := []float64{}
+
+	for _, centroid := range centroids {
+		scatters = append(scatters, clusterScatter(d.metric, centroid, superCluster.ClusteredPoints()))
+	}
+
+	cumRatio := 0.0
+
+	for i, centroid := range centroids {
+		maxRatio := 0.0
+
+		for j, otherCentroid := range centroids {
+			if i == j {
+				continue
+			}
+
+			ratio := (scatters[i] + scatters[j]) / d.metric.Dist(centroid, otherCentroid)
+			maxRatio = math.Max(maxRatio, ratio)
+		}
+
+		cumRatio += maxRatio
+	}
+
+	return cumRatio / float64(len(centroids))
+}
+
+func (d *daviesBouldinIndex) Better(a, b float64) bool {
+	return a < b
+}
+
+func (d *daviesBouldinIndex) String() string {
+	return DaviesBouldinIndex
+}
+
+type calinskiHarabaszIndex struct {
+	metric DistanceMetric
+}
+
+func (c *calinskiHarabaszIndex) Score(superCluster FuzzySuperCluster) float64 {
+	centroids := nonEmptyCentroids(superCluster)
+	points := superCluster.ClusteredPoints()
+
+	if len(centroids) < 2 || len(points) <= len(centroids) {
+		return math.NaN()
+	}
+
+	center := NewFuzzyPoint(meanCoords(points), "")
+	betweenDisp := 0.0
+	withinDisp := 0.0
+
+	for _, centroid := range centroids {
+		size := 0
+
+		for _, point := range points {
+			if point.BestFitClusterIdx != centroid.BestFitClusterIdx {
+				continue
+			}
+
+			withinDisp += squaredDist(c.metric, c.metric.Dist(point, centroid))
+			size++
+		}
+
+		betweenDisp += float64(size) * squaredDist(c.metric, c.metric.Dist(centroid, center))
+	}
+
+	clusterCount := float64(len(centroids))
+	pointCount := float64(len(points))
+
+	return (betweenDisp / (clusterCount - 1)) / (withinDisp / (pointCount - clusterCount))
+}
+
+func (c *calinskiHarabaszIndex) Better(a, b float64) bool {
+	return a > b
+}
+
+func (c *calinskiHarabaszIndex) String() string {
+	return CalinskiHarabaszIndex
+}
+
+type xieBeniIndex struct {
+	metric DistanceMetric
+}
+
+func (x *xieBeniIndex) Score(superCluster FuzzySuperCluster) float64 {
+	centroids := nonEmptyCentroids(superCluster)
+	points := superCluster.ClusteredPoints()
+
+	if len(centroids) < 2 || len(points) == 0 {
+		return math.NaN()
+	}
+
+	compactness := 0.0
+
+	for _, centroid := range centroids {
+		for _, point := range points {
+			membershipDegree := point.MembershipDegree(centroid.BestFitClusterIdx)
+			compactness += math.Pow(membershipDegree, XieBeniFuzzifier) * squaredDist(x.metric, x.metric.Dist(point, centroid))
+		}
+	}
+
+	minSeparation := math.Inf(0)
+
+	for i, centroid := range centroids {
+		for j := i + 1; j < len(centroids); j++ {
+			minSeparation = math.Min(minSeparation, squaredDist(x.metric, x.metric.Dist(centroid, centroids[j])))
+		}
+	}
+
+	return compactness / (float64(len(points)) * minSeparation)
+}
+
+func (x *xieBeniIndex) Better(a, b float64) bool {
+	return a < b
+}
+
+func (x *xieBeniIndex) String() string {
+	return XieBeniIndex
+}
+
+// nonEmptyCentroids leaves out centroids of clusters without points since they have no meaningful position.
+func nonEmptyCentroids(superCluster FuzzySuperCluster) []*FuzzyPoint {
+	centroids := []*FuzzyPoint{}
+
+	for _, centroid := range superCluster.Centroids() {
+		for _, point := range superCluster.ClusteredPoints() {
+			if point.BestFitClusterIdx == centroid.BestFitClusterIdx {
+				centroids = append(centroids, centroid)
+				break
+			}
+		}
+	}
+
+	return centroids
+}
+
+func clusterScatter(metric DistanceMetric, centroid *FuzzyPoint, points []*FuzzyPoint) float64 {
+	cumDist := 0.0
+	size := 0
+
+	for _, point := range points {
+		if point.BestFitClusterIdx != centroid.BestFitClusterIdx {
+			continue
+		}
+
+		cumDist += metric.Dist(point, centroid)
+		size++
+	}
+
+	return cumDist / float64(size)
+}
+
+func meanCoords(points []*FuzzyPoint) []float64 {
+	coords := make([]float64, points[0].DimCount())
+
+	for _, point := range points {
+		for dim, coord := range point.Coords {
+			coords[dim] += coord
+		}
+	}
+
+	for dim := range coords {
+		coords[dim] /= float64(len(points))
+	}
+
+	return coords
+}
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	clr "github.com/IvanHristov98/postato/cluster"
+	"github.com/IvanHristov98/postato/evaluation"
 	"github.com/IvanHristov98/postato/fuzzy/inference"
+	"github.com/IvanHristov98/postato/fuzzy/norm"
 	"github.com/IvanHristov98/postato/fuzzy/number"
 	fn "github.com/IvanHristov98/postato/fuzzy/number"
+	"github.com/IvanHristov98/postato/model"
 	"github.com/IvanHristov98/postato/plot"
 	"github.com/akamensky/argparse"
 )
@@ -23,46 +30,212 @@ const (
 	GenDir         = "GENDIR"
 	ImageDirName   = "image"
 	FoldCrossCount = 10
+	TestRatio      = 0.2
+	NoGroupColumn  = -1
 )
 
 type config struct {
-	dataset string
-	fnType  string
+	dataset      string
+	groupColumn  int
+	fnType       string
+	normType     string
+	infererType  string
+	ruleSetOpts  *fn.RuleSetOptions
+	seed         int64
+	modelPath    string
+	input        string
+	output       string
+	split        string
+	foldCount    int
+	repeatCount  int
+	testRatio    float64
+	unstratified bool
+	reportFormat string
+	reportOut    string
 }
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
-
 	parser := argparse.NewParser("postato", "Guesses human body position")
 
-	d := parser.String("d", "dataset", &argparse.Options{Required: true, Help: "Path to training dataset. Must be a CSV."})
-
-	fuzzyNumTypes := []string{number.GaussianFuzzyNum, number.TriangularFuzzyNum}
-	t := parser.Selector("t", "type", fuzzyNumTypes, &argparse.Options{Required: false, Default: number.GaussianFuzzyNum})
+	d := parser.String("d", "dataset", &argparse.Options{Required: false, Help: "Path to training dataset. Must be a CSV."})
+
+	gc := parser.Int("", "group-column", &argparse.Options{Required: false, Default: NoGroupColumn, Help: "Zero based index of the CSV column identifying the subject of a reading. It must come after the features and before the activity."})
+
+	t := parser.Selector("t", "type", number.RuleSetTypes(), &argparse.Options{Required: false, Default: number.GaussianFuzzyNum})
+	n := parser.Selector("n", "norm", norm.Names(), &argparse.Options{Required: false, Default: norm.MinimumNorm, Help: "T-norm and s-norm pair used by the inferer."})
+	inf := parser.Selector("", "inferer", inference.Names(), &argparse.Options{Required: false, Default: inference.MamdaniInference, Help: "Type-1 Mamdani or interval type-2 inference with Karnik-Mendel type reduction."})
+	g := parser.Selector("g", "generation", fn.RuleGenerationModes(), &argparse.Options{Required: false, Default: fn.GlobalClustering, Help: "Whether to cluster all points together or each activity separately."})
+	k := parser.Int("k", "clusters", &argparse.Options{Required: false, Default: fn.OptimalClusterCount, Help: "Cluster count overall or per activity."})
+	ak := parser.StringList("", "activity-clusters", &argparse.Options{Required: false, Help: "Cluster count of a single activity in per-activity mode given as activity=count."})
+	a := parser.Selector("a", "algorithm", fn.ClusteringAlgorithms(), &argparse.Options{Required: false, Default: fn.KMeansClustering, Help: "Clustering algorithm used to generate rules."})
+	fuzzifier := parser.Float("", "fuzzifier", &argparse.Options{Required: false, Default: clr.DefaultFuzzifier, Help: "Fuzzifier m of fuzzy c-means and Gustafson-Kessel. Must be greater than 1."})
+	tolerance := parser.Float("", "tolerance", &argparse.Options{Required: false, Default: clr.DefaultConvergenceTolerance, Help: "Largest centroid shift of k-means or membership degree change of fuzzy c-means at which a clustering restart converges."})
+	maxIter := parser.Int("", "max-iter", &argparse.Options{Required: false, Default: clr.DefaultMaxIterCount, Help: "Max iteration count of each clustering restart."})
+	v := parser.Selector("v", "validity-index", clr.ValidityIndexNames(), &argparse.Options{Required: false, Help: "Selects the cluster count by the given validity index instead of using -k."})
+	minK := parser.Int("", "min-clusters", &argparse.Options{Required: false, Default: fn.MinSearchedClusterCount, Help: "Smallest cluster count searched with -v."})
+	maxK := parser.Int("", "max-clusters", &argparse.Options{Required: false, Default: fn.MaxSearchedClusterCount, Help: "Largest cluster count searched with -v."})
+	seedArg := parser.String("s", "seed", &argparse.Options{Required: false, Help: "Random seed making runs reproducible. Defaults to the current time."})
+	fitting := parser.Selector("", "fitting", fn.FittingStrategies(), &argparse.Options{Required: false, Help: "Strategy fitting the bounds of each cluster on each axis. Defaults to ellipsoid for gk and averaged otherwise."})
+	fallback := parser.Selector("", "bounds-fallback", fn.BoundsFallbackPolicies(), &argparse.Options{Required: false, Default: fn.FailFallback, Help: "What to do with a cluster whose bounds can't be fitted on an axis."})
+	parallelism := parser.Int("", "parallelism", &argparse.Options{Required: false, Default: clr.DefaultParallelism, Help: "Clustering restarts run at once. 0 uses GOMAXPROCS."})
+	progress := parser.Flag("", "progress", &argparse.Options{Required: false, Help: "Logs the objective of every clustering iteration."})
+	emptyClusters := parser.Selector("", "empty-clusters", clr.EmptyClusterStrategies(), &argparse.Options{Required: false, Default: clr.DefaultEmptyClusterStrategy, Help: "How k-means recovers a cluster which lost all its points."})
+	distance := parser.Selector("", "distance", clr.DistanceMetricNames(), &argparse.Options{Required: false, Default: clr.EuclideanDistance, Help: "Distance metric used for clustering, membership degrees and validity indices."})
 
 	drawCmd := parser.NewCommand("draw", "Draws fuzzy numbers.")
 	testCmd := parser.NewCommand("test", "Runs a fold cross validation of the fuzzy inference system.")
+	split := testCmd.Selector("", "split", evaluation.SplitNames(), &argparse.Options{Required: false, Default: evaluation.KFoldSplit, Help: "How points are split into training and test sets."})
+	f := testCmd.Int("f", "folds", &argparse.Options{Required: false, Default: FoldCrossCount, Help: "Fold count of k-fold cross validation."})
+	repeats := testCmd.Int("", "repeats", &argparse.Options{Required: false, Default: 1, Help: "How many times k-fold cross validation is repeated with a new shuffle."})
+	testRatio := testCmd.Float("", "test-ratio", &argparse.Options{Required: false, Default: TestRatio, Help: "Share of points held out for testing."})
+	reportFormat := testCmd.Selector("", "report-format", evaluation.FormatNames(), &argparse.Options{Required: false, Default: evaluation.TextFormat, Help: "Format of the evaluation report."})
+	reportOut := testCmd.String("", "report-out", &argparse.Options{Required: false, Help: "Path to write the evaluation report to. Defaults to stdout."})
+	unstratified := testCmd.Flag("", "unstratified", &argparse.Options{Required: false, Help: "Splits without preserving the activity proportions."})
+
+	trainCmd := parser.NewCommand("train", "Builds a fuzzy rule set and saves it as a model file.")
+
+	classifyCmd := parser.NewCommand("classify", "Classifies every reading of an unlabeled dataset with a saved model.")
+
+	o := trainCmd.String("o", "output", &argparse.Options{Required: true, Help: "Path to the model file to write."})
+	m := classifyCmd.String("m", "model", &argparse.Options{Required: true, Help: "Path to a model file produced by train."})
+	i := classifyCmd.String("i", "input", &argparse.Options{Required: true, Help: "Path to the readings to classify. Must be a CSV."})
+	predOut := classifyCmd.String("o", "output", &argparse.Options{Required: true, Help: "Path to the predictions CSV to write."})
 
 	if err := parser.Parse(os.Args); err != nil {
 		log.Fatalf("Error parsing arguments: %s", err)
 	}
 
-	cfg := &config{dataset: *d, fnType: *t}
+	seed, err := parseSeed(*seedArg)
+	if err != nil {
+		log.Fatalf("Error parsing arguments: %s", err)
+	}
+
+	ruleSetOpts, err := newRuleSetOptions(*g, *k, *ak)
+	if err != nil {
+		log.Fatalf("Error parsing arguments: %s", err)
+	}
+
+	ruleSetOpts.Algorithm = *a
+	ruleSetOpts.Fuzzifier = *fuzzifier
+	ruleSetOpts.Tolerance = *tolerance
+	ruleSetOpts.MaxIterCount = *maxIter
+	ruleSetOpts.ValidityIndex = *v
+	ruleSetOpts.Seed = seed
+	ruleSetOpts.MinClusterCount = *minK
+	ruleSetOpts.MaxClusterCount = *maxK
+	ruleSetOpts.BoundsFitting = *fitting
+	ruleSetOpts.BoundsFallback = *fallback
+	ruleSetOpts.Parallelism = *parallelism
+	ruleSetOpts.EmptyClusterStrategy = *emptyClusters
+	ruleSetOpts.DistanceMetric = *distance
+
+	if *progress {
+		ruleSetOpts.Progress = logProgress
+	}
+
+	cfg := &config{dataset: *d, groupColumn: *gc, fnType: *t, normType: *n, infererType: *inf, ruleSetOpts: ruleSetOpts, seed: seed}
+
+	ctx, stop := interruptibleContext()
+	defer stop()
 
 	if drawCmd.Happened() {
-		drawFuzzyNumbers(cfg)
+		requireDataset(cfg)
+		drawFuzzyNumbers(ctx, cfg)
 	} else if testCmd.Happened() {
-		crossFold(cfg)
+		requireDataset(cfg)
+		cfg.split = *split
+		cfg.foldCount = *f
+		cfg.repeatCount = *repeats
+		cfg.testRatio = *testRatio
+		cfg.unstratified = *unstratified
+		cfg.reportFormat = *reportFormat
+		cfg.reportOut = *reportOut
+		crossFold(ctx, cfg)
+	} else if trainCmd.Happened() {
+		requireDataset(cfg)
+		cfg.modelPath = *o
+		train(ctx, cfg)
+	} else if classifyCmd.Happened() {
+		cfg.modelPath = *m
+		cfg.input = *i
+		cfg.output = *predOut
+		classify(cfg)
+	}
+}
+
+// interruptibleContext is cancelled on interrupt so clustering stops at its next iteration.
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	go func() {
+		select {
+		case <-interrupts:
+			log.Printf("Interrupted, stopping clustering")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(interrupts)
+		cancel()
+	}
+}
+
+func logProgress(restartIdx, iteration int, objective float64) {
+	log.Printf("Clustering restart %d iteration %d has objective %f", restartIdx, iteration, objective)
+}
+
+func parseSeed(seedArg string) (int64, error) {
+	if seedArg == "" {
+		return time.Now().UnixNano(), nil
+	}
+
+	seed, err := strconv.ParseInt(seedArg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid seed %s: %s", seedArg, err)
+	}
+
+	return seed, nil
+}
+
+func newRuleSetOptions(mode string, clusterCount int, activityClusterCounts []string) (*fn.RuleSetOptions, error) {
+	opts := fn.DefaultRuleSetOptions()
+	opts.Mode = mode
+	opts.ClusterCount = clusterCount
+
+	for _, activityClusterCount := range activityClusterCounts {
+		parts := strings.SplitN(activityClusterCount, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid activity cluster count %s, expected activity=count", activityClusterCount)
+		}
+
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cluster count of activity %s: %s", parts[0], err)
+		}
+
+		opts.ActivityClusterCounts[parts[0]] = count
 	}
+
+	return opts, nil
 }
 
-func drawFuzzyNumbers(cfg *config) {
-	points, err := parsePoints(cfg.dataset)
+func requireDataset(cfg *config) {
+	if cfg.dataset == "" {
+		log.Fatalf("Error parsing arguments: [-d|--dataset] is required")
+	}
+}
+
+func drawFuzzyNumbers(ctx context.Context, cfg *config) {
+	points, err := parsePoints(cfg.dataset, cfg.groupColumn)
 	if err != nil {
 		log.Fatalf("Error reading points: %s", err)
 	}
 
-	fuzzyRuleSet, err := fn.NewFuzzyRuleSet(cfg.fnType, points)
+	fuzzyRuleSet, _, err := fn.NewFuzzyRuleSet(ctx, cfg.fnType, points, cfg.ruleSetOpts)
 
 	if err != nil {
 		log.Fatalf("Error building fuzzy numbers from clusters: %s", err)
@@ -70,67 +243,273 @@ func drawFuzzyNumbers(cfg *config) {
 
 	cleanUpImages()
 
-	if err := drawAllImages(fuzzyRuleSet); err != nil {
+	if err := drawAllImages(fuzzyRuleSet, rand.New(rand.NewSource(cfg.seed))); err != nil {
 		log.Fatalf("Error drawing fuzzy numbers: %s", err)
 	}
 
 	log.Println("Fuzzy number drawing completed.")
 }
 
-func crossFold(cfg *config) {
-	points, err := parsePoints(cfg.dataset)
+func train(ctx context.Context, cfg *config) {
+	points, err := parsePoints(cfg.dataset, cfg.groupColumn)
 	if err != nil {
 		log.Fatalf("Error reading points: %s", err)
 	}
 
-	rand.Shuffle(len(points), func(i, j int) { points[i], points[j] = points[j], points[i] })
-	cumAccuracy := 0.0
+	checksum, err := model.DatasetChecksum(cfg.dataset)
+	if err != nil {
+		log.Fatalf("Error computing dataset checksum: %s", err)
+	}
+
+	fuzzyRuleSet, diagnostics, err := fn.NewFuzzyRuleSet(ctx, cfg.fnType, points, cfg.ruleSetOpts)
+	if err != nil {
+		log.Fatalf("Error building fuzzy numbers from clusters: %s", err)
+	}
+
+	clusterCount, activityClusterCounts := clusterCountMetadata(cfg.ruleSetOpts, diagnostics)
+
+	metadata := model.Metadata{
+		FuzzyNumType:          cfg.fnType,
+		FeatureCount:          FeatureCount,
+		RuleGenerationMode:    cfg.ruleSetOpts.Mode,
+		ClusteringAlgorithm:   cfg.ruleSetOpts.Algorithm,
+		ClusterCount:          clusterCount,
+		ActivityClusterCounts: activityClusterCounts,
+		RestartCount:          cfg.ruleSetOpts.RestartCount,
+		ValidityIndex:         cfg.ruleSetOpts.ValidityIndex,
+		Fuzzifier:             fuzzifierMetadata(cfg.ruleSetOpts),
+		BoundsFitting:         cfg.ruleSetOpts.EffectiveBoundsFitting(),
+		BoundsFallback:        cfg.ruleSetOpts.BoundsFallback,
+		EmptyClusterStrategy:  emptyClusterStrategyMetadata(cfg.ruleSetOpts),
+		DistanceMetric:        cfg.ruleSetOpts.DistanceMetric,
+		Seed:                  cfg.seed,
+		DatasetChecksum:       checksum,
+		TrainedAt:             time.Now().UTC(),
+		Diagnostics:           diagnostics,
+	}
 
-	for i := 0; i < FoldCrossCount; i++ {
-		trainingPoints := append(points[:int(len(points)*i/FoldCrossCount)], points[int(len(points)*(i+1)/FoldCrossCount):]...)
+	if err := model.Save(cfg.modelPath, model.NewModel(metadata, fuzzyRuleSet)); err != nil {
+		log.Fatalf("Error saving model: %s", err)
+	}
 
-		fuzzyRuleSet, err := fn.NewFuzzyRuleSet(cfg.fnType, trainingPoints)
+	log.Printf("Model saved to %s.\n", cfg.modelPath)
+}
 
-		if err != nil {
-			log.Fatalf("Error building fuzzy numbers from clusters: %s", err)
+func classify(cfg *config) {
+	m, err := model.Load(cfg.modelPath)
+	if err != nil {
+		log.Fatalf("Error loading model: %s", err)
+	}
+
+	if m.Metadata.FeatureCount != FeatureCount {
+		log.Fatalf("Model expects %d features but %d are supported", m.Metadata.FeatureCount, FeatureCount)
+	}
+
+	records, err := readCSVFile(cfg.input)
+	if err != nil {
+		log.Fatalf("Error reading readings: %s", err)
+	}
+
+	points, err := pointsFromRecords(records, m.Metadata.FeatureCount, NoGroupColumn)
+	if err != nil {
+		log.Fatalf("Error reading points: %s", err)
+	}
+
+	inferer, err := newInferer(cfg, m.RuleSet)
+	if err != nil {
+		log.Fatalf("Error creating inferer: %s", err)
+	}
+
+	predictions := [][]string{}
+
+	for i, point := range points {
+		classification := inferer.Infer(point)
+		membershipDegree := strconv.FormatFloat(classification.FiringStrength, 'f', -1, 64)
+
+		prediction := append(append([]string{}, records[i]...), classification.Activity, membershipDegree)
+		predictions = append(predictions, prediction)
+	}
+
+	if err := writeCSVFile(cfg.output, predictions); err != nil {
+		log.Fatalf("Error writing predictions: %s", err)
+	}
+
+	log.Printf("Classified %d readings into %s.\n", len(predictions), cfg.output)
+}
+
+func emptyClusterStrategyMetadata(opts *fn.RuleSetOptions) string {
+	if opts.Algorithm != fn.KMeansClustering {
+		return ""
+	}
+
+	return opts.EmptyClusterStrategy
+}
+
+func fuzzifierMetadata(opts *fn.RuleSetOptions) float64 {
+	if opts.Algorithm != fn.FuzzyCMeansClustering && opts.Algorithm != fn.GustafsonKesselClustering {
+		return 0
+	}
+
+	return opts.Fuzzifier
+}
+
+// clusterCountMetadata returns the cluster counts rules were generated from which differ from the configured ones
+// when a validity index selected them.
+func clusterCountMetadata(opts *fn.RuleSetOptions, diagnostics *fn.Diagnostics) (int, map[string]int) {
+	if opts.Mode == fn.GlobalClustering {
+		if len(diagnostics.ClusterCountSelections) > 0 {
+			return diagnostics.ClusterCountSelections[0].ClusterCount, nil
 		}
 
-		inferer := inference.NewMamdaniInferer(fuzzyRuleSet)
+		return opts.ClusterCount, nil
+	}
+
+	activityClusterCounts := make(map[string]int)
+
+	for activity, clusterCount := range opts.ActivityClusterCounts {
+		activityClusterCounts[activity] = clusterCount
+	}
 
-		testPoints := points[int(len(points)*i/10):int(len(points)*(i+1)/FoldCrossCount)]
+	for _, selection := range diagnostics.ClusterCountSelections {
+		activityClusterCounts[selection.Activity] = selection.ClusterCount
+	}
 
-		successCnt := 0
+	// Every activity either has a fixed cluster count or got one selected.
+	if opts.ValidityIndex != "" {
+		return 0, activityClusterCounts
+	}
 
-		for _, testPoint := range testPoints {
-			activity := inferer.ClassifyActivity(testPoint)
+	return opts.ClusterCount, activityClusterCounts
+}
 
-			if testPoint.Activity == activity {
-				successCnt++
-			}
+func crossFold(ctx context.Context, cfg *config) {
+	points, err := parsePoints(cfg.dataset, cfg.groupColumn)
+	if err != nil {
+		log.Fatalf("Error reading points: %s", err)
+	}
+
+	rnd := rand.New(rand.NewSource(cfg.seed))
+
+	splitter, err := newSplitter(cfg, rnd)
+	if err != nil {
+		log.Fatalf("Error creating splitter: %s", err)
+	}
+
+	report, err := evaluation.CrossValidate(points, splitter, func(trainingPoints []*clr.FuzzyPoint) (inference.FuzzyInferer, error) {
+		fuzzyRuleSet, _, err := fn.NewFuzzyRuleSet(ctx, cfg.fnType, trainingPoints, cfg.ruleSetOpts)
+		if err != nil {
+			return nil, fmt.Errorf("Error building fuzzy numbers from clusters: %s", err)
 		}
 
-		accuracy := 100.0 * float64(successCnt) / float64(len(testPoints))
-		log.Printf("Accuracy of fold cross %d iteration is %2.f perc.\n", i, accuracy)
+		return newInferer(cfg, fuzzyRuleSet)
+	})
+	if err != nil {
+		log.Fatalf("Error evaluating fuzzy inference system: %s", err)
+	}
+
+	log.Printf("Average accuracy of %d splits is %2.f perc.\n", len(report.Folds), 100.0*report.Accuracy.Mean)
 
-		cumAccuracy += accuracy
+	checksum, err := model.DatasetChecksum(cfg.dataset)
+	if err != nil {
+		log.Fatalf("Error computing dataset checksum: %s", err)
 	}
 
-	avgAccuracy := cumAccuracy / float64(FoldCrossCount)
-	log.Printf("Average accuracy of %d fold cross is %2.f perc.\n", FoldCrossCount, avgAccuracy)
+	report.Config = reportConfig(cfg, checksum)
+
+	if err := writeReport(cfg, report); err != nil {
+		log.Fatalf("Error writing evaluation report: %s", err)
+	}
 }
 
-func parsePoints(path string) ([]*clr.FuzzyPoint, error) {
-	points := []*clr.FuzzyPoint{}
+func reportConfig(cfg *config, checksum string) map[string]interface{} {
+	return map[string]interface{}{
+		"dataset":            cfg.dataset,
+		"datasetChecksum":    checksum,
+		"fuzzyNumType":       cfg.fnType,
+		"norm":               cfg.normType,
+		"inferer":            cfg.infererType,
+		"ruleGenerationMode": cfg.ruleSetOpts.Mode,
+		"algorithm":          cfg.ruleSetOpts.Algorithm,
+		"clusterCount":       cfg.ruleSetOpts.ClusterCount,
+		"validityIndex":      cfg.ruleSetOpts.ValidityIndex,
+		"boundsFitting":      cfg.ruleSetOpts.EffectiveBoundsFitting(),
+		"boundsFallback":     cfg.ruleSetOpts.BoundsFallback,
+		"emptyClusters":      emptyClusterStrategyMetadata(cfg.ruleSetOpts),
+		"distance":           cfg.ruleSetOpts.DistanceMetric,
+		"split":              cfg.split,
+		"foldCount":          cfg.foldCount,
+		"repeatCount":        cfg.repeatCount,
+		"testRatio":          cfg.testRatio,
+		"stratified":         !cfg.unstratified,
+		"seed":               cfg.seed,
+	}
+}
+
+func writeReport(cfg *config, report *evaluation.Report) error {
+	if cfg.reportOut == "" {
+		return report.Write(os.Stdout, cfg.reportFormat)
+	}
+
+	f, err := os.Create(cfg.reportOut)
+	if err != nil {
+		return fmt.Errorf("Unable to create report file: %s", err)
+	}
+
+	if err := report.Write(f, cfg.reportFormat); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Error closing report file: %s", err)
+	}
+
+	return nil
+}
+
+func newSplitter(cfg *config, rnd *rand.Rand) (evaluation.Splitter, error) {
+	switch cfg.split {
+	case evaluation.LeaveOneGroupOutSplit:
+		return evaluation.NewLeaveOneGroupOutSplitter(), nil
+	case evaluation.GroupKFoldSplit:
+		return evaluation.NewGroupKFoldSplitter(cfg.foldCount)
+	case evaluation.HoldOutSplit:
+		return evaluation.NewHoldOutSplitter(cfg.testRatio, !cfg.unstratified, rnd)
+	default:
+		return evaluation.NewKFoldSplitter(cfg.foldCount, cfg.repeatCount, !cfg.unstratified, rnd)
+	}
+}
+
+func newInferer(cfg *config, fuzzyRuleSet fn.FuzzyRuleSet) (inference.FuzzyInferer, error) {
+	n, err := norm.NewNorm(cfg.normType)
+	if err != nil {
+		return nil, err
+	}
 
+	return inference.NewInferer(cfg.infererType, fuzzyRuleSet, n)
+}
+
+func parsePoints(path string, groupColumn int) ([]*clr.FuzzyPoint, error) {
 	records, err := readCSVFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("Error reading points: %s", err)
 	}
 
+	return pointsFromRecords(records, FeatureCount, groupColumn)
+}
+
+// pointsFromRecords reads featureCount features from each record followed by an optional activity.
+func pointsFromRecords(records [][]string, featureCount int, groupColumn int) ([]*clr.FuzzyPoint, error) {
+	points := []*clr.FuzzyPoint{}
+
 	for i, record := range records {
+		if len(record) < featureCount {
+			return nil, fmt.Errorf("Record on line %d has %d columns but %d features are expected", i+1, len(record), featureCount)
+		}
+
 		coords := []float64{}
 
-		for j := 0; j < FeatureCount; j++ {
+		for j := 0; j < featureCount; j++ {
 			col := record[j]
 			coord, err := strconv.ParseFloat(col, 64)
 			if err != nil {
@@ -140,13 +519,23 @@ func parsePoints(path string) ([]*clr.FuzzyPoint, error) {
 			coords = append(coords, coord)
 		}
 
-		activity := record[len(record)-1]
+		activity := ""
 
-		if isNum(activity) {
-			activity = ""
+		// Unlabeled records may end right after their features.
+		if len(record) > featureCount && !isNum(record[len(record)-1]) {
+			activity = record[len(record)-1]
 		}
 
 		point := clr.NewFuzzyPoint(coords, activity)
+
+		if groupColumn != NoGroupColumn {
+			if groupColumn < featureCount || groupColumn >= len(record)-1 {
+				return nil, fmt.Errorf("Group column %d of record %d must be between the features and the activity", groupColumn, i)
+			}
+
+			point.Group = record[groupColumn]
+		}
+
 		points = append(points, point)
 	}
 
@@ -174,17 +563,48 @@ func readCSVFile(path string) ([][]string, error) {
 	return records, nil
 }
 
-func drawAllImages(fuzzyRuleSet fn.FuzzyRuleSet) error {
-	for activity, fuzzyNums := range fuzzyRuleSet {
-		for fnIdx, fuzzyNum := range fuzzyNums {
-			imageName := fmt.Sprintf("fn_%s_%d.png", activity, fnIdx)
-			path, err := imagePath(imageName)
-			if err != nil {
-				return err
-			}
+func writeCSVFile(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Unable to create output file: %s", err)
+	}
+
+	writer := csv.NewWriter(f)
+	if err := writer.WriteAll(records); err != nil {
+		f.Close()
+		return fmt.Errorf("Error writing CSV records: %s", err)
+	}
+
+	// Closing may be the first to report that the written records didn't make it to disk.
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Error closing output file: %s", err)
+	}
+
+	return nil
+}
+
+func drawAllImages(fuzzyRuleSet fn.FuzzyRuleSet, rnd *rand.Rand) error {
+	activities := []string{}
+
+	for activity := range fuzzyRuleSet {
+		activities = append(activities, activity)
+	}
 
-			if err := plot.DrawFuzzyNums(fuzzyNum, -GridBound, GridBound, fnIdx, activity, path); err != nil {
-				return fmt.Errorf("Error drawing fuzzy number %d in activity %s: %s", fnIdx, activity, err)
+	// Curve colors are drawn in order so activities are sorted to keep them reproducible.
+	sort.Strings(activities)
+
+	for _, activity := range activities {
+		for ruleIdx, fuzzyNums := range fuzzyRuleSet[activity] {
+			for fnIdx, fuzzyNum := range fuzzyNums {
+				imageName := fmt.Sprintf("fn_%s_%d_%d.png", activity, ruleIdx, fnIdx)
+				path, err := imagePath(imageName)
+				if err != nil {
+					return err
+				}
+
+				if err := plot.DrawFuzzyNums(fuzzyNum, -GridBound, GridBound, fnIdx, activity, path, rnd); err != nil {
+					return fmt.Errorf("Error drawing fuzzy number %d of rule %d in activity %s: %s", fnIdx, ruleIdx, activity, err)
+				}
 			}
 		}
 	}
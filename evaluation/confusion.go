@@ -0,0 +1,135 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// NoRuleFired labels predictions for which the inferer fired no rule.
+const NoRuleFired = "<none>"
+
+// ConfusionMatrix counts predictions per actual activity.
+type ConfusionMatrix struct {
+	// counts maps actual to predicted activity to count.
+	counts map[string]map[string]int
+	total  int
+}
+
+func NewConfusionMatrix() *ConfusionMatrix {
+	return &ConfusionMatrix{
+		counts: make(map[string]map[string]int),
+	}
+}
+
+// Add records a prediction. An empty predicted activity means that no rule fired.
+func (c *ConfusionMatrix) Add(actual, predicted string) {
+	if predicted == "" {
+		predicted = NoRuleFired
+	}
+
+	if _, ok := c.counts[actual]; !ok {
+		c.counts[actual] = make(map[string]int)
+	}
+
+	c.counts[actual][predicted]++
+	c.total++
+}
+
+// Merge adds all counts of other to c.
+func (c *ConfusionMatrix) Merge(other *ConfusionMatrix) {
+	for actual, predictions := range other.counts {
+		for predicted, count := range predictions {
+			if _, ok := c.counts[actual]; !ok {
+				c.counts[actual] = make(map[string]int)
+			}
+
+			c.counts[actual][predicted] += count
+		}
+	}
+
+	c.total += other.total
+}
+
+func (c *ConfusionMatrix) Count(actual, predicted string) int {
+	return c.counts[actual][predicted]
+}
+
+func (c *ConfusionMatrix) Total() int {
+	return c.total
+}
+
+// Activities returns the sorted union of actual and predicted activities without NoRuleFired.
+func (c *ConfusionMatrix) Activities() []string {
+	seen := make(map[string]bool)
+
+	for actual, predictions := range c.counts {
+		seen[actual] = true
+
+		for predicted := range predictions {
+			seen[predicted] = true
+		}
+	}
+
+	delete(seen, NoRuleFired)
+
+	activities := []string{}
+
+	for activity := range seen {
+		activities = append(activities, activity)
+	}
+
+	sort.Strings(activities)
+
+	return activities
+}
+
+func (c *ConfusionMatrix) support(activity string) int {
+	support := 0
+
+	for _, count := range c.counts[activity] {
+		support += count
+	}
+
+	return support
+}
+
+func (c *ConfusionMatrix) predicted(activity string) int {
+	predicted := 0
+
+	for _, predictions := range c.counts {
+		predicted += predictions[activity]
+	}
+
+	return predicted
+}
+
+// MarshalJSON encodes the matrix as rows of actual and columns of predicted activities.
+func (c *ConfusionMatrix) MarshalJSON() ([]byte, error) {
+	activities := c.Activities()
+	predicted := append(append([]string{}, activities...), NoRuleFired)
+	counts := [][]int{}
+
+	for _, actual := range activities {
+		row := []int{}
+
+		for _, column := range predicted {
+			row = append(row, c.Count(actual, column))
+		}
+
+		counts = append(counts, row)
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	// Keeps NoRuleFired readable instead of escaping its angle brackets.
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(struct {
+		Actual    []string `json:"actual"`
+		Predicted []string `json:"predicted"`
+		Counts    [][]int  `json:"counts"`
+	}{activities, predicted, counts})
+
+	return buf.Bytes(), err
+}
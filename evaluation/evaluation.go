@@ -0,0 +1,41 @@
+package evaluation
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/IvanHristov98/postato/cluster"
+	"github.com/IvanHristov98/postato/fuzzy/inference"
+)
+
+// Trainer builds an inferer out of training points.
+type Trainer func(points []*cluster.FuzzyPoint) (inference.FuzzyInferer, error)
+
+// CrossValidate trains and tests an inferer on every split of the points.
+func CrossValidate(points []*cluster.FuzzyPoint, splitter Splitter, train Trainer) (*Report, error) {
+	splits, err := splitter.Splits(points)
+	if err != nil {
+		return nil, fmt.Errorf("Error splitting points: %s", err)
+	}
+
+	folds := []*Fold{}
+
+	for _, split := range splits {
+		inferer, err := train(split.Training)
+		if err != nil {
+			return nil, fmt.Errorf("Error training on split %s: %s", split.Name, err)
+		}
+
+		confusion := NewConfusionMatrix()
+
+		for _, testPoint := range split.Test {
+			confusion.Add(testPoint.Activity, inferer.ClassifyActivity(testPoint))
+		}
+
+		log.Printf("Accuracy of split %s is %2.f perc.\n", split.Name, 100.0*NewMetrics(confusion).Accuracy)
+
+		folds = append(folds, &Fold{Name: split.Name, Confusion: confusion})
+	}
+
+	return NewReport(folds), nil
+}
@@ -0,0 +1,104 @@
+package evaluation
+
+// ClassMetrics holds the one-vs-rest metrics of a single activity.
+type ClassMetrics struct {
+	Activity  string  `json:"activity"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// Metrics summarizes a confusion matrix. Predictions without a fired rule count as wrong.
+type Metrics struct {
+	// Name identifies the split the metrics were computed on.
+	Name              string          `json:"name,omitempty"`
+	Accuracy          float64         `json:"accuracy"`
+	Classes           []*ClassMetrics `json:"classes"`
+	MacroPrecision    float64         `json:"macroPrecision"`
+	MacroRecall       float64         `json:"macroRecall"`
+	MacroF1           float64         `json:"macroF1"`
+	WeightedPrecision float64         `json:"weightedPrecision"`
+	WeightedRecall    float64         `json:"weightedRecall"`
+	WeightedF1        float64         `json:"weightedF1"`
+	Kappa             float64         `json:"kappa"`
+	NoRuleFired       int             `json:"noRuleFired"`
+	Total             int             `json:"total"`
+}
+
+func NewMetrics(c *ConfusionMatrix) *Metrics {
+	metrics := &Metrics{
+		Classes: []*ClassMetrics{},
+		Total:   c.Total(),
+	}
+
+	if c.Total() == 0 {
+		return metrics
+	}
+
+	correct := 0
+	total := float64(c.Total())
+	expectedAgreement := 0.0
+
+	for _, activity := range c.Activities() {
+		classMetrics := newClassMetrics(c, activity)
+		metrics.Classes = append(metrics.Classes, classMetrics)
+
+		correct += c.Count(activity, activity)
+		expectedAgreement += float64(classMetrics.Support) / total * float64(c.predicted(activity)) / total
+
+		weight := float64(classMetrics.Support) / total
+		metrics.WeightedPrecision += weight * classMetrics.Precision
+		metrics.WeightedRecall += weight * classMetrics.Recall
+		metrics.WeightedF1 += weight * classMetrics.F1
+
+		metrics.MacroPrecision += classMetrics.Precision
+		metrics.MacroRecall += classMetrics.Recall
+		metrics.MacroF1 += classMetrics.F1
+	}
+
+	classCount := float64(len(metrics.Classes))
+	metrics.MacroPrecision /= classCount
+	metrics.MacroRecall /= classCount
+	metrics.MacroF1 /= classCount
+
+	metrics.Accuracy = float64(correct) / total
+	metrics.Kappa = cohensKappa(metrics.Accuracy, expectedAgreement)
+	metrics.NoRuleFired = c.predicted(NoRuleFired)
+
+	return metrics
+}
+
+func newClassMetrics(c *ConfusionMatrix, activity string) *ClassMetrics {
+	truePositives := float64(c.Count(activity, activity))
+	support := c.support(activity)
+	predicted := c.predicted(activity)
+
+	classMetrics := &ClassMetrics{
+		Activity: activity,
+		Support:  support,
+	}
+
+	// Undefined ratios are reported as 0 like most evaluation tools do.
+	if predicted > 0 {
+		classMetrics.Precision = truePositives / float64(predicted)
+	}
+
+	if support > 0 {
+		classMetrics.Recall = truePositives / float64(support)
+	}
+
+	if classMetrics.Precision+classMetrics.Recall > 0 {
+		classMetrics.F1 = 2 * classMetrics.Precision * classMetrics.Recall / (classMetrics.Precision + classMetrics.Recall)
+	}
+
+	return classMetrics
+}
+
+func cohensKappa(observedAgreement, expectedAgreement float64) float64 {
+	if expectedAgreement == 1 {
+		return 0
+	}
+
+	return (observedAgreement - expectedAgreement) / (1 - expectedAgreement)
+}
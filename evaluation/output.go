@@ -0,0 +1,200 @@
+package evaluation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	CSVFormat  = "csv"
+)
+
+func FormatNames() []string {
+	return []string{TextFormat, JSONFormat, CSVFormat}
+}
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case TextFormat:
+		return r.WriteText(w)
+	case JSONFormat:
+		return r.WriteJSON(w)
+	case CSVFormat:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("Invalid report format provided %s", format)
+	}
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	activities := r.Confusion.Activities()
+	columns := append(append([]string{}, activities...), NoRuleFired)
+
+	fmt.Fprintf(tw, "Configuration\n")
+
+	for _, key := range r.configKeys() {
+		fmt.Fprintf(tw, "%s\t%v\t\n", key, r.Config[key])
+	}
+
+	fmt.Fprintf(tw, "\nSplit\tAccuracy\tMacro F1\tKappa\tNo rule fired\tTotal\t\n")
+
+	for _, fold := range r.Folds {
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%.4f\t%d\t%d\t\n", fold.Name, fold.Accuracy, fold.MacroF1, fold.Kappa, fold.NoRuleFired, fold.Total)
+	}
+
+	fmt.Fprintf(tw, "\nConfusion matrix (rows are actual, columns are predicted activities)\n")
+	fmt.Fprintf(tw, "\t")
+
+	for _, column := range columns {
+		fmt.Fprintf(tw, "%s\t", column)
+	}
+
+	fmt.Fprintf(tw, "\n")
+
+	for _, actual := range activities {
+		fmt.Fprintf(tw, "%s\t", actual)
+
+		for _, predicted := range columns {
+			fmt.Fprintf(tw, "%d\t", r.Confusion.Count(actual, predicted))
+		}
+
+		fmt.Fprintf(tw, "\n")
+	}
+
+	fmt.Fprintf(tw, "\nActivity\tPrecision\tRecall\tF1\tSupport\t\n")
+
+	for _, class := range r.Classes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t\n", class.Activity, class.Precision, class.Recall, class.F1, class.Support)
+	}
+
+	fmt.Fprintf(tw, "macro avg\t%s\t%s\t%s\t%d\t\n", r.MacroPrecision, r.MacroRecall, r.MacroF1, r.Confusion.Total())
+	fmt.Fprintf(tw, "weighted avg\t%s\t%s\t%s\t%d\t\n", r.WeightedPrecision, r.WeightedRecall, r.WeightedF1, r.Confusion.Total())
+
+	fmt.Fprintf(tw, "\nAccuracy\t%s\t\n", r.Accuracy)
+	fmt.Fprintf(tw, "Cohen's kappa\t%s\t\n", r.Kappa)
+	fmt.Fprintf(tw, "No rule fired\t%d (%s per fold)\t\n", r.Confusion.predicted(NoRuleFired), r.NoRuleFired)
+
+	return tw.Flush()
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one scope,split,activity,metric,value row per value so reports are easy to diff and filter.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"scope", "split", "activity", "metric", "value"}}
+
+	for _, key := range r.configKeys() {
+		records = append(records, []string{"config", "", "", key, fmt.Sprintf("%v", r.Config[key])})
+	}
+
+	for _, fold := range r.Folds {
+		records = append(records, metricsRecords(fold)...)
+	}
+
+	records = append(records, summaryRecord("accuracy", r.Accuracy)...)
+	records = append(records, summaryRecord("macroPrecision", r.MacroPrecision)...)
+	records = append(records, summaryRecord("macroRecall", r.MacroRecall)...)
+	records = append(records, summaryRecord("macroF1", r.MacroF1)...)
+	records = append(records, summaryRecord("weightedPrecision", r.WeightedPrecision)...)
+	records = append(records, summaryRecord("weightedRecall", r.WeightedRecall)...)
+	records = append(records, summaryRecord("weightedF1", r.WeightedF1)...)
+	records = append(records, summaryRecord("kappa", r.Kappa)...)
+	records = append(records, summaryRecord("noRuleFired", r.NoRuleFired)...)
+
+	for _, class := range r.Classes {
+		records = append(records, classSummaryRecords(class)...)
+	}
+
+	for _, actual := range r.Confusion.Activities() {
+		for _, predicted := range append(r.Confusion.Activities(), NoRuleFired) {
+			metric := fmt.Sprintf("predicted:%s", predicted)
+			records = append(records, []string{"confusion", "", actual, metric, strconv.Itoa(r.Confusion.Count(actual, predicted))})
+		}
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("Error writing CSV report: %s", err)
+	}
+
+	return nil
+}
+
+func (r *Report) configKeys() []string {
+	keys := []string{}
+
+	for key := range r.Config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func metricsRecords(m *Metrics) [][]string {
+	records := [][]string{
+		{"fold", m.Name, "", "accuracy", formatFloat(m.Accuracy)},
+		{"fold", m.Name, "", "macroPrecision", formatFloat(m.MacroPrecision)},
+		{"fold", m.Name, "", "macroRecall", formatFloat(m.MacroRecall)},
+		{"fold", m.Name, "", "macroF1", formatFloat(m.MacroF1)},
+		{"fold", m.Name, "", "weightedPrecision", formatFloat(m.WeightedPrecision)},
+		{"fold", m.Name, "", "weightedRecall", formatFloat(m.WeightedRecall)},
+		{"fold", m.Name, "", "weightedF1", formatFloat(m.WeightedF1)},
+		{"fold", m.Name, "", "kappa", formatFloat(m.Kappa)},
+		{"fold", m.Name, "", "noRuleFired", strconv.Itoa(m.NoRuleFired)},
+		{"fold", m.Name, "", "total", strconv.Itoa(m.Total)},
+	}
+
+	for _, class := range m.Classes {
+		records = append(records,
+			[]string{"fold", m.Name, class.Activity, "precision", formatFloat(class.Precision)},
+			[]string{"fold", m.Name, class.Activity, "recall", formatFloat(class.Recall)},
+			[]string{"fold", m.Name, class.Activity, "f1", formatFloat(class.F1)},
+			[]string{"fold", m.Name, class.Activity, "support", strconv.Itoa(class.Support)},
+		)
+	}
+
+	return records
+}
+
+func summaryRecord(metric string, summary Summary) [][]string {
+	return [][]string{
+		{"aggregate", "", "", metric + "Mean", formatFloat(summary.Mean)},
+		{"aggregate", "", "", metric + "StdDev", formatFloat(summary.StdDev)},
+	}
+}
+
+func classSummaryRecords(class *ClassSummary) [][]string {
+	records := [][]string{}
+
+	for _, metric := range []struct {
+		name    string
+		summary Summary
+	}{{"precision", class.Precision}, {"recall", class.Recall}, {"f1", class.F1}} {
+		records = append(records,
+			[]string{"aggregate", "", class.Activity, metric.name + "Mean", formatFloat(metric.summary.Mean)},
+			[]string{"aggregate", "", class.Activity, metric.name + "StdDev", formatFloat(metric.summary.StdDev)},
+		)
+	}
+
+	return append(records, []string{"aggregate", "", class.Activity, "support", strconv.Itoa(class.Support)})
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
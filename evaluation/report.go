@@ -0,0 +1,153 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Summary aggregates a metric over folds.
+type Summary struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+}
+
+type ClassSummary struct {
+	Activity  string  `json:"activity"`
+	Precision Summary `json:"precision"`
+	Recall    Summary `json:"recall"`
+	F1        Summary `json:"f1"`
+	// Support is summed over all folds.
+	Support int `json:"support"`
+}
+
+// Fold holds the predictions made on the test set of a single split.
+type Fold struct {
+	Name      string
+	Confusion *ConfusionMatrix
+}
+
+// Report aggregates the metrics of all folds of an evaluation.
+type Report struct {
+	// Config describes the run which produced the report. It is filled by the caller.
+	Config map[string]interface{} `json:"config"`
+	Folds  []*Metrics             `json:"folds"`
+	// Confusion pools the predictions of all folds.
+	Confusion         *ConfusionMatrix `json:"confusion"`
+	Accuracy          Summary          `json:"accuracy"`
+	MacroPrecision    Summary          `json:"macroPrecision"`
+	MacroRecall       Summary          `json:"macroRecall"`
+	MacroF1           Summary          `json:"macroF1"`
+	WeightedPrecision Summary          `json:"weightedPrecision"`
+	WeightedRecall    Summary          `json:"weightedRecall"`
+	WeightedF1        Summary          `json:"weightedF1"`
+	Kappa             Summary          `json:"kappa"`
+	NoRuleFired       Summary          `json:"noRuleFired"`
+	Classes           []*ClassSummary  `json:"classes"`
+}
+
+func NewReport(folds []*Fold) *Report {
+	report := &Report{
+		Config:    make(map[string]interface{}),
+		Folds:     []*Metrics{},
+		Confusion: NewConfusionMatrix(),
+		Classes:   []*ClassSummary{},
+	}
+
+	for _, fold := range folds {
+		metrics := NewMetrics(fold.Confusion)
+		metrics.Name = fold.Name
+
+		report.Folds = append(report.Folds, metrics)
+		report.Confusion.Merge(fold.Confusion)
+	}
+
+	report.Accuracy = summarize(report.Folds, func(m *Metrics) float64 { return m.Accuracy })
+	report.MacroPrecision = summarize(report.Folds, func(m *Metrics) float64 { return m.MacroPrecision })
+	report.MacroRecall = summarize(report.Folds, func(m *Metrics) float64 { return m.MacroRecall })
+	report.MacroF1 = summarize(report.Folds, func(m *Metrics) float64 { return m.MacroF1 })
+	report.WeightedPrecision = summarize(report.Folds, func(m *Metrics) float64 { return m.WeightedPrecision })
+	report.WeightedRecall = summarize(report.Folds, func(m *Metrics) float64 { return m.WeightedRecall })
+	report.WeightedF1 = summarize(report.Folds, func(m *Metrics) float64 { return m.WeightedF1 })
+	report.Kappa = summarize(report.Folds, func(m *Metrics) float64 { return m.Kappa })
+	report.NoRuleFired = summarize(report.Folds, func(m *Metrics) float64 { return float64(m.NoRuleFired) })
+
+	for _, activity := range report.Confusion.Activities() {
+		report.Classes = append(report.Classes, report.classSummary(activity))
+	}
+
+	return report
+}
+
+// classSummary only considers folds in which the activity occurs.
+func (r *Report) classSummary(activity string) *ClassSummary {
+	classFolds := []*ClassMetrics{}
+
+	for _, fold := range r.Folds {
+		for _, classMetrics := range fold.Classes {
+			if classMetrics.Activity == activity {
+				classFolds = append(classFolds, classMetrics)
+			}
+		}
+	}
+
+	precisions := []float64{}
+	recalls := []float64{}
+	f1s := []float64{}
+
+	for _, classMetrics := range classFolds {
+		precisions = append(precisions, classMetrics.Precision)
+		recalls = append(recalls, classMetrics.Recall)
+		f1s = append(f1s, classMetrics.F1)
+	}
+
+	return &ClassSummary{
+		Activity:  activity,
+		Precision: newSummary(precisions),
+		Recall:    newSummary(recalls),
+		F1:        newSummary(f1s),
+		Support:   r.Confusion.support(activity),
+	}
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%.4f ± %.4f", s.Mean, s.StdDev)
+}
+
+func summarize(folds []*Metrics, metric func(m *Metrics) float64) Summary {
+	values := []float64{}
+
+	for _, fold := range folds {
+		values = append(values, metric(fold))
+	}
+
+	return newSummary(values)
+}
+
+// newSummary uses the sample standard deviation since folds are a sample of possible splits.
+func newSummary(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	mean := 0.0
+
+	for _, value := range values {
+		mean += value
+	}
+
+	mean /= float64(len(values))
+
+	if len(values) == 1 {
+		return Summary{Mean: mean}
+	}
+
+	variance := 0.0
+
+	for _, value := range values {
+		variance += math.Pow(value-mean, 2)
+	}
+
+	variance /= float64(len(values) - 1)
+
+	return Summary{Mean: mean, StdDev: math.Sqrt(variance)}
+}
@@ -0,0 +1,329 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const (
+	KFoldSplit            = "kfold"
+	HoldOutSplit          = "holdout"
+	LeaveOneGroupOutSplit = "leave-one-group-out"
+	GroupKFoldSplit       = "group-kfold"
+)
+
+// Split holds a training and a test set. Both are fresh slices that never alias the split points.
+type Split struct {
+	Name     string
+	Training []*cluster.FuzzyPoint
+	Test     []*cluster.FuzzyPoint
+}
+
+type Splitter interface {
+	Splits(points []*cluster.FuzzyPoint) ([]*Split, error)
+}
+
+func SplitNames() []string {
+	return []string{KFoldSplit, HoldOutSplit, LeaveOneGroupOutSplit, GroupKFoldSplit}
+}
+
+type kFoldSplitter struct {
+	foldCount   int
+	repeatCount int
+	stratified  bool
+	rnd         *rand.Rand
+}
+
+// NewKFoldSplitter reshuffles the points on each of the repeatCount repetitions.
+func NewKFoldSplitter(foldCount, repeatCount int, stratified bool, rnd *rand.Rand) (Splitter, error) {
+	if foldCount < 2 {
+		return nil, fmt.Errorf("Fold count must be at least 2, got %d", foldCount)
+	}
+
+	if repeatCount < 1 {
+		return nil, fmt.Errorf("Repeat count must be positive, got %d", repeatCount)
+	}
+
+	return &kFoldSplitter{
+		foldCount:   foldCount,
+		repeatCount: repeatCount,
+		stratified:  stratified,
+		rnd:         rnd,
+	}, nil
+}
+
+func (k *kFoldSplitter) Splits(points []*cluster.FuzzyPoint) ([]*Split, error) {
+	if len(points) < k.foldCount {
+		return nil, fmt.Errorf("Can't split %d points into %d folds", len(points), k.foldCount)
+	}
+
+	splits := []*Split{}
+
+	for r := 0; r < k.repeatCount; r++ {
+		foldIdxs := make([]int, len(points))
+
+		for i, pointIdx := range shuffledIdxs(points, k.stratified, k.rnd) {
+			// Dealing the points one by one keeps every activity evenly spread when stratified.
+			foldIdxs[pointIdx] = i % k.foldCount
+		}
+
+		for fold := 0; fold < k.foldCount; fold++ {
+			split := splitByFold(points, foldIdxs, fold)
+			split.Name = fmt.Sprintf("repeat %d fold %d", r, fold)
+
+			splits = append(splits, split)
+		}
+	}
+
+	return splits, nil
+}
+
+type holdOutSplitter struct {
+	testRatio  float64
+	stratified bool
+	rnd        *rand.Rand
+}
+
+func NewHoldOutSplitter(testRatio float64, stratified bool, rnd *rand.Rand) (Splitter, error) {
+	if testRatio <= 0 || testRatio >= 1 {
+		return nil, fmt.Errorf("Test ratio must be in (0, 1), got %f", testRatio)
+	}
+
+	return &holdOutSplitter{
+		testRatio:  testRatio,
+		stratified: stratified,
+		rnd:        rnd,
+	}, nil
+}
+
+func (h *holdOutSplitter) Splits(points []*cluster.FuzzyPoint) ([]*Split, error) {
+	const testFold, trainingFold = 0, 1
+
+	foldIdxs := make([]int, len(points))
+	groups := [][]int{shuffledIdxs(points, false, h.rnd)}
+
+	if h.stratified {
+		groups = activityIdxs(points, h.rnd)
+	}
+
+	for _, group := range groups {
+		testCount := int(math.Round(h.testRatio * float64(len(group))))
+
+		for i, pointIdx := range group {
+			foldIdxs[pointIdx] = trainingFold
+
+			if i < testCount {
+				foldIdxs[pointIdx] = testFold
+			}
+		}
+	}
+
+	split := splitByFold(points, foldIdxs, testFold)
+	split.Name = HoldOutSplit
+
+	if len(split.Training) == 0 || len(split.Test) == 0 {
+		return nil, fmt.Errorf("Hold out of %d points with test ratio %f leaves an empty set", len(points), h.testRatio)
+	}
+
+	return []*Split{split}, nil
+}
+
+type leaveOneGroupOutSplitter struct{}
+
+// NewLeaveOneGroupOutSplitter tests on the points of each group after training on all other groups.
+func NewLeaveOneGroupOutSplitter() Splitter {
+	return &leaveOneGroupOutSplitter{}
+}
+
+func (l *leaveOneGroupOutSplitter) Splits(points []*cluster.FuzzyPoint) ([]*Split, error) {
+	groups, foldIdxs, err := groupIdxs(points)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) < 2 {
+		return nil, fmt.Errorf("Leaving one group out needs at least 2 groups, got %d", len(groups))
+	}
+
+	splits := []*Split{}
+
+	for fold, group := range groups {
+		split := splitByFold(points, foldIdxs, fold)
+		split.Name = fmt.Sprintf("group %s", group)
+
+		splits = append(splits, split)
+	}
+
+	return splits, nil
+}
+
+type groupKFoldSplitter struct {
+	foldCount int
+}
+
+// NewGroupKFoldSplitter keeps all points of a group in the same fold while balancing fold sizes.
+func NewGroupKFoldSplitter(foldCount int) (Splitter, error) {
+	if foldCount < 2 {
+		return nil, fmt.Errorf("Fold count must be at least 2, got %d", foldCount)
+	}
+
+	return &groupKFoldSplitter{foldCount: foldCount}, nil
+}
+
+func (g *groupKFoldSplitter) Splits(points []*cluster.FuzzyPoint) ([]*Split, error) {
+	groups, groupOfPoint, err := groupIdxs(points)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) < g.foldCount {
+		return nil, fmt.Errorf("Can't split %d groups into %d folds", len(groups), g.foldCount)
+	}
+
+	groupSizes := make([]int, len(groups))
+
+	for _, groupIdx := range groupOfPoint {
+		groupSizes[groupIdx]++
+	}
+
+	// The largest groups are placed first, each into the currently smallest fold.
+	order := make([]int, len(groups))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return groupSizes[order[i]] > groupSizes[order[j]] })
+
+	foldSizes := make([]int, g.foldCount)
+	foldOfGroup := make([]int, len(groups))
+
+	for _, groupIdx := range order {
+		smallestFold := 0
+
+		for fold, size := range foldSizes {
+			if size < foldSizes[smallestFold] {
+				smallestFold = fold
+			}
+		}
+
+		foldOfGroup[groupIdx] = smallestFold
+		foldSizes[smallestFold] += groupSizes[groupIdx]
+	}
+
+	foldIdxs := make([]int, len(points))
+
+	for i, groupIdx := range groupOfPoint {
+		foldIdxs[i] = foldOfGroup[groupIdx]
+	}
+
+	splits := []*Split{}
+
+	for fold := 0; fold < g.foldCount; fold++ {
+		split := splitByFold(points, foldIdxs, fold)
+		split.Name = fmt.Sprintf("group fold %d", fold)
+
+		splits = append(splits, split)
+	}
+
+	return splits, nil
+}
+
+// groupIdxs returns the sorted group names and the index of the group of each point.
+func groupIdxs(points []*cluster.FuzzyPoint) ([]string, []int, error) {
+	seen := make(map[string]bool)
+
+	for i, point := range points {
+		if point.Group == "" {
+			return nil, nil, fmt.Errorf("Point %d has no group", i)
+		}
+
+		seen[point.Group] = true
+	}
+
+	groups := []string{}
+
+	for group := range seen {
+		groups = append(groups, group)
+	}
+
+	sort.Strings(groups)
+
+	idxOfGroup := make(map[string]int, len(groups))
+
+	for i, group := range groups {
+		idxOfGroup[group] = i
+	}
+
+	groupOfPoint := make([]int, len(points))
+
+	for i, point := range points {
+		groupOfPoint[i] = idxOfGroup[point.Group]
+	}
+
+	return groups, groupOfPoint, nil
+}
+
+// shuffledIdxs returns shuffled point indices. When stratified the indices are grouped by activity.
+func shuffledIdxs(points []*cluster.FuzzyPoint, stratified bool, rnd *rand.Rand) []int {
+	if !stratified {
+		return rnd.Perm(len(points))
+	}
+
+	idxs := []int{}
+
+	for _, group := range activityIdxs(points, rnd) {
+		idxs = append(idxs, group...)
+	}
+
+	return idxs
+}
+
+// activityIdxs returns the shuffled point indices of each activity ordered by activity name.
+func activityIdxs(points []*cluster.FuzzyPoint, rnd *rand.Rand) [][]int {
+	groups := make(map[string][]int)
+
+	for i, point := range points {
+		groups[point.Activity] = append(groups[point.Activity], i)
+	}
+
+	activities := []string{}
+
+	for activity := range groups {
+		activities = append(activities, activity)
+	}
+
+	sort.Strings(activities)
+
+	idxs := [][]int{}
+
+	for _, activity := range activities {
+		group := groups[activity]
+		rnd.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+
+		idxs = append(idxs, group)
+	}
+
+	return idxs
+}
+
+func splitByFold(points []*cluster.FuzzyPoint, foldIdxs []int, testFold int) *Split {
+	split := &Split{
+		Training: []*cluster.FuzzyPoint{},
+		Test:     []*cluster.FuzzyPoint{},
+	}
+
+	for i, point := range points {
+		if foldIdxs[i] == testFold {
+			split.Test = append(split.Test, point)
+		} else {
+			split.Training = append(split.Training, point)
+		}
+	}
+
+	return split
+}
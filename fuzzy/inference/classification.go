@@ -0,0 +1,57 @@
+package inference
+
+import "sort"
+
+// ActivityScore explains how strongly the rules of an activity fired for a point.
+type ActivityScore struct {
+	Activity string
+	// FiringStrength is the s-norm aggregation of the firing strengths of all rules of the activity.
+	FiringStrength float64
+	// Interval is set only by interval type-2 inferers. FiringStrength is then the type reduced score.
+	Interval *FiringInterval
+	Rules    []*RuleScore
+}
+
+// RuleScore explains how strongly a single rule fired for a point.
+type RuleScore struct {
+	FiringStrength float64
+	// DimMembershipDegrees holds the membership degree of each coordinate in the fuzzy number of its dimension.
+	DimMembershipDegrees []float64
+	// Interval is set only by interval type-2 inferers. FiringStrength is then its middle.
+	Interval *FiringInterval
+}
+
+// Classification is the outcome of inferring the activity of a single point.
+type Classification struct {
+	// Activity is empty when Unknown is set.
+	Activity       string
+	FiringStrength float64
+	// Unknown is set when no rule fired, i.e. every firing strength is the minimal membership degree.
+	Unknown bool
+	// Ranked holds the scores of all activities ordered by decreasing firing strength.
+	Ranked []*ActivityScore
+}
+
+func newClassification(scores []*ActivityScore) *Classification {
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].FiringStrength != scores[j].FiringStrength {
+			return scores[i].FiringStrength > scores[j].FiringStrength
+		}
+
+		return scores[i].Activity < scores[j].Activity
+	})
+
+	classification := &Classification{
+		FiringStrength: MinMembershipDegree,
+		Unknown:        true,
+		Ranked:         scores,
+	}
+
+	if len(scores) > 0 && scores[0].FiringStrength > MinMembershipDegree {
+		classification.Activity = scores[0].Activity
+		classification.FiringStrength = scores[0].FiringStrength
+		classification.Unknown = false
+	}
+
+	return classification
+}
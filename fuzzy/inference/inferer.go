@@ -1,7 +1,34 @@
 package inference
 
-import "github.com/IvanHristov98/postato/cluster"
+import (
+	"fmt"
+
+	"github.com/IvanHristov98/postato/cluster"
+	"github.com/IvanHristov98/postato/fuzzy/norm"
+	"github.com/IvanHristov98/postato/fuzzy/number"
+)
+
+const (
+	MamdaniInference       = "mamdani"
+	IntervalType2Inference = "interval-type-2"
+)
 
 type FuzzyInferer interface {
 	ClassifyActivity(point *cluster.FuzzyPoint) string
+	Infer(point *cluster.FuzzyPoint) *Classification
+}
+
+func Names() []string {
+	return []string{MamdaniInference, IntervalType2Inference}
+}
+
+func NewInferer(name string, ruleSet number.FuzzyRuleSet, n norm.Norm) (FuzzyInferer, error) {
+	switch name {
+	case MamdaniInference:
+		return NewMamdaniInferer(ruleSet, n), nil
+	case IntervalType2Inference:
+		return NewIntervalType2Inferer(ruleSet, n), nil
+	default:
+		return nil, fmt.Errorf("Invalid inferer provided %s", name)
+	}
 }
@@ -2,6 +2,7 @@ package inference
 
 import (
 	"github.com/IvanHristov98/postato/cluster"
+	"github.com/IvanHristov98/postato/fuzzy/norm"
 	"github.com/IvanHristov98/postato/fuzzy/number"
 )
 
@@ -12,41 +13,57 @@ const (
 
 type mamdaniInferer struct {
 	ruleSet number.FuzzyRuleSet
+	norm    norm.Norm
 }
 
-func NewMamdaniInferer(ruleSet number.FuzzyRuleSet) FuzzyInferer {
+func NewMamdaniInferer(ruleSet number.FuzzyRuleSet, n norm.Norm) FuzzyInferer {
 	return &mamdaniInferer{
 		ruleSet: ruleSet,
+		norm:    n,
 	}
 }
 
 func (m *mamdaniInferer) ClassifyActivity(point *cluster.FuzzyPoint) string {
-	maxMembershipDegree := MinMembershipDegree
-	bestFitActivity := ""
+	return m.Infer(point).Activity
+}
+
+func (m *mamdaniInferer) Infer(point *cluster.FuzzyPoint) *Classification {
+	scores := []*ActivityScore{}
 
 	for activity := range m.ruleSet {
-		membershipDegree := m.activityMembershipDegree(point, activity)
+		scores = append(scores, m.activityScore(point, activity))
+	}
+
+	return newClassification(scores)
+}
 
-		if maxMembershipDegree < membershipDegree {
-			maxMembershipDegree = membershipDegree
-			bestFitActivity = activity
-		}
+func (m *mamdaniInferer) activityScore(point *cluster.FuzzyPoint, activity string) *ActivityScore {
+	ruleScores := []*RuleScore{}
+	firingStrengths := []float64{}
+
+	for _, rule := range m.ruleSet[activity] {
+		ruleScore := m.ruleScore(point, rule)
+
+		ruleScores = append(ruleScores, ruleScore)
+		firingStrengths = append(firingStrengths, ruleScore.FiringStrength)
 	}
 
-	return bestFitActivity
+	return &ActivityScore{
+		Activity:       activity,
+		FiringStrength: norm.Or(m.norm, firingStrengths...),
+		Rules:          ruleScores,
+	}
 }
 
-func (m *mamdaniInferer) activityMembershipDegree(point *cluster.FuzzyPoint, activity string) float64 {
-	rule := m.ruleSet[activity]
-	minMembershipDegree := MaxMembershipDegree
+func (m *mamdaniInferer) ruleScore(point *cluster.FuzzyPoint, rule number.FuzzyRule) *RuleScore {
+	dimMembershipDegrees := []float64{}
 
 	for i, fuzzyNum := range rule {
-		membershipDegree := fuzzyNum.MembershipDegree(point.Coords[i])
-
-		if membershipDegree < minMembershipDegree {
-			minMembershipDegree = membershipDegree
-		}
+		dimMembershipDegrees = append(dimMembershipDegrees, fuzzyNum.MembershipDegree(point.Coords[i]))
 	}
 
-	return minMembershipDegree
+	return &RuleScore{
+		FiringStrength:       norm.And(m.norm, dimMembershipDegrees...),
+		DimMembershipDegrees: dimMembershipDegrees,
+	}
 }
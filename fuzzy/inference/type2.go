@@ -0,0 +1,187 @@
+package inference
+
+import (
+	"sort"
+
+	"github.com/IvanHristov98/postato/cluster"
+	"github.com/IvanHristov98/postato/fuzzy/norm"
+	"github.com/IvanHristov98/postato/fuzzy/number"
+)
+
+// FiringInterval bounds the firing strength of a rule or activity of an interval type-2 inferer.
+type FiringInterval struct {
+	Lower float64
+	Upper float64
+}
+
+// intervalType2Inferer fires each rule with an interval and scores each activity by the Karnik-Mendel
+// type reduced center of sets of a consequent which is 1 for the activity and 0 for all others.
+type intervalType2Inferer struct {
+	ruleSet number.FuzzyRuleSet
+	norm    norm.Norm
+}
+
+func NewIntervalType2Inferer(ruleSet number.FuzzyRuleSet, n norm.Norm) FuzzyInferer {
+	return &intervalType2Inferer{
+		ruleSet: ruleSet,
+		norm:    n,
+	}
+}
+
+func (t *intervalType2Inferer) ClassifyActivity(point *cluster.FuzzyPoint) string {
+	return t.Infer(point).Activity
+}
+
+func (t *intervalType2Inferer) Infer(point *cluster.FuzzyPoint) *Classification {
+	scores := []*ActivityScore{}
+
+	for activity := range t.ruleSet {
+		scores = append(scores, t.activityScore(point, activity))
+	}
+
+	// Sorting makes the floating point sums of the type reduction independent of map order.
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Activity < scores[j].Activity })
+
+	lowers := []float64{}
+	uppers := []float64{}
+
+	for _, score := range scores {
+		lowers = append(lowers, score.Interval.Lower)
+		uppers = append(uppers, score.Interval.Upper)
+	}
+
+	for i, score := range scores {
+		consequents := make([]float64, len(scores))
+		consequents[i] = MaxMembershipDegree
+
+		left, right := karnikMendel(consequents, lowers, uppers)
+		score.FiringStrength = (left + right) / 2
+	}
+
+	return newClassification(scores)
+}
+
+func (t *intervalType2Inferer) activityScore(point *cluster.FuzzyPoint, activity string) *ActivityScore {
+	ruleScores := []*RuleScore{}
+	lowers := []float64{}
+	uppers := []float64{}
+
+	for _, rule := range t.ruleSet[activity] {
+		ruleScore := t.ruleScore(point, rule)
+
+		ruleScores = append(ruleScores, ruleScore)
+		lowers = append(lowers, ruleScore.Interval.Lower)
+		uppers = append(uppers, ruleScore.Interval.Upper)
+	}
+
+	return &ActivityScore{
+		Activity: activity,
+		Interval: &FiringInterval{
+			Lower: norm.Or(t.norm, lowers...),
+			Upper: norm.Or(t.norm, uppers...),
+		},
+		Rules: ruleScores,
+	}
+}
+
+func (t *intervalType2Inferer) ruleScore(point *cluster.FuzzyPoint, rule number.FuzzyRule) *RuleScore {
+	dimMembershipDegrees := []float64{}
+	lowers := []float64{}
+	uppers := []float64{}
+
+	for i, fuzzyNum := range rule {
+		lower, upper := number.MembershipInterval(fuzzyNum, point.Coords[i])
+
+		dimMembershipDegrees = append(dimMembershipDegrees, (lower+upper)/2)
+		lowers = append(lowers, lower)
+		uppers = append(uppers, upper)
+	}
+
+	interval := &FiringInterval{
+		Lower: norm.And(t.norm, lowers...),
+		Upper: norm.And(t.norm, uppers...),
+	}
+
+	return &RuleScore{
+		FiringStrength:       (interval.Lower + interval.Upper) / 2,
+		DimMembershipDegrees: dimMembershipDegrees,
+		Interval:             interval,
+	}
+}
+
+// karnikMendel type reduces the center of sets with the given consequents and firing intervals.
+// It returns the least and the greatest weighted average of the consequents reachable within the intervals.
+func karnikMendel(consequents, lowers, uppers []float64) (float64, float64) {
+	order := make([]int, len(consequents))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return consequents[order[i]] < consequents[order[j]] })
+
+	ys := make([]float64, len(order))
+	ls := make([]float64, len(order))
+	us := make([]float64, len(order))
+
+	for i, idx := range order {
+		ys[i], ls[i], us[i] = consequents[idx], lowers[idx], uppers[idx]
+	}
+
+	// The left end point weighs the low consequents with their upper firing strengths and the right one the other way around.
+	left := karnikMendelEndPoint(ys, us, ls)
+	right := karnikMendelEndPoint(ys, ls, us)
+
+	return left, right
+}
+
+// karnikMendelEndPoint iterates the switch point between consequents weighted below and above it.
+// The consequents must be sorted ascending.
+func karnikMendelEndPoint(ys, belowWeights, aboveWeights []float64) float64 {
+	weights := make([]float64, len(ys))
+
+	for i := range ys {
+		weights[i] = (belowWeights[i] + aboveWeights[i]) / 2
+	}
+
+	y, ok := weightedAverage(ys, weights)
+	if !ok {
+		return MinMembershipDegree
+	}
+
+	// The switch point moves monotonically so the loop ends after at most len(ys) iterations.
+	for iter := 0; iter <= len(ys); iter++ {
+		for i := range ys {
+			if ys[i] <= y {
+				weights[i] = belowWeights[i]
+			} else {
+				weights[i] = aboveWeights[i]
+			}
+		}
+
+		nextY, ok := weightedAverage(ys, weights)
+		if !ok || nextY == y {
+			break
+		}
+
+		y = nextY
+	}
+
+	return y
+}
+
+func weightedAverage(values, weights []float64) (float64, bool) {
+	numer := 0.0
+	denom := 0.0
+
+	for i := range values {
+		numer += values[i] * weights[i]
+		denom += weights[i]
+	}
+
+	if denom <= 0 {
+		return 0.0, false
+	}
+
+	return numer / denom, true
+}
@@ -0,0 +1,190 @@
+package norm
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	MinimumNorm     = "minimum"
+	ProductNorm     = "product"
+	LukasiewiczNorm = "lukasiewicz"
+	HamacherNorm    = "hamacher"
+	EinsteinNorm    = "einstein"
+	YagerNorm       = "yager"
+
+	// Gamma of 0 gives the Hamacher product.
+	DefaultHamacherGamma = 0.0
+	DefaultYagerP        = 2.0
+
+	minDegree = 0.0
+	maxDegree = 1.0
+)
+
+// Norm pairs a t-norm used for AND with its dual s-norm used for OR.
+type Norm interface {
+	TNorm(a, b float64) float64
+	SNorm(a, b float64) float64
+	String() string
+}
+
+func Names() []string {
+	return []string{MinimumNorm, ProductNorm, LukasiewiczNorm, HamacherNorm, EinsteinNorm, YagerNorm}
+}
+
+func NewNorm(name string) (Norm, error) {
+	switch name {
+	case MinimumNorm:
+		return &minimumNorm{}, nil
+	case ProductNorm:
+		return &productNorm{}, nil
+	case LukasiewiczNorm:
+		return &lukasiewiczNorm{}, nil
+	case HamacherNorm:
+		return NewHamacherNorm(DefaultHamacherGamma)
+	case EinsteinNorm:
+		return &einsteinNorm{}, nil
+	case YagerNorm:
+		return NewYagerNorm(DefaultYagerP)
+	default:
+		return nil, fmt.Errorf("Invalid norm provided %s", name)
+	}
+}
+
+// And folds degrees with the t-norm. The empty conjunction is fully true.
+func And(n Norm, degrees ...float64) float64 {
+	result := maxDegree
+
+	for _, degree := range degrees {
+		result = n.TNorm(result, degree)
+	}
+
+	return result
+}
+
+// Or folds degrees with the s-norm. The empty disjunction is fully false.
+func Or(n Norm, degrees ...float64) float64 {
+	result := minDegree
+
+	for _, degree := range degrees {
+		result = n.SNorm(result, degree)
+	}
+
+	return result
+}
+
+type minimumNorm struct{}
+
+func (m *minimumNorm) TNorm(a, b float64) float64 {
+	return math.Min(a, b)
+}
+
+func (m *minimumNorm) SNorm(a, b float64) float64 {
+	return math.Max(a, b)
+}
+
+func (m *minimumNorm) String() string {
+	return MinimumNorm
+}
+
+type productNorm struct{}
+
+func (p *productNorm) TNorm(a, b float64) float64 {
+	return a * b
+}
+
+func (p *productNorm) SNorm(a, b float64) float64 {
+	return a + b - a*b
+}
+
+func (p *productNorm) String() string {
+	return ProductNorm
+}
+
+type lukasiewiczNorm struct{}
+
+func (l *lukasiewiczNorm) TNorm(a, b float64) float64 {
+	return math.Max(minDegree, a+b-1)
+}
+
+func (l *lukasiewiczNorm) SNorm(a, b float64) float64 {
+	return math.Min(maxDegree, a+b)
+}
+
+func (l *lukasiewiczNorm) String() string {
+	return LukasiewiczNorm
+}
+
+type hamacherNorm struct {
+	gamma float64
+}
+
+func NewHamacherNorm(gamma float64) (Norm, error) {
+	if gamma < 0 {
+		return nil, fmt.Errorf("Hamacher gamma must be non-negative, got %f", gamma)
+	}
+
+	return &hamacherNorm{gamma: gamma}, nil
+}
+
+func (h *hamacherNorm) TNorm(a, b float64) float64 {
+	denom := h.gamma + (1-h.gamma)*(a+b-a*b)
+	if denom == 0 {
+		return minDegree
+	}
+
+	return a * b / denom
+}
+
+func (h *hamacherNorm) SNorm(a, b float64) float64 {
+	denom := 1 + (h.gamma-1)*a*b
+	if denom == 0 {
+		return maxDegree
+	}
+
+	return (a + b + (h.gamma-2)*a*b) / denom
+}
+
+func (h *hamacherNorm) String() string {
+	return fmt.Sprintf("%s(gamma: %f)", HamacherNorm, h.gamma)
+}
+
+type einsteinNorm struct{}
+
+func (e *einsteinNorm) TNorm(a, b float64) float64 {
+	return a * b / (2 - (a + b - a*b))
+}
+
+func (e *einsteinNorm) SNorm(a, b float64) float64 {
+	return (a + b) / (1 + a*b)
+}
+
+func (e *einsteinNorm) String() string {
+	return EinsteinNorm
+}
+
+type yagerNorm struct {
+	p float64
+}
+
+func NewYagerNorm(p float64) (Norm, error) {
+	if p <= 0 {
+		return nil, fmt.Errorf("Yager p must be positive, got %f", p)
+	}
+
+	return &yagerNorm{p: p}, nil
+}
+
+func (y *yagerNorm) TNorm(a, b float64) float64 {
+	sum := math.Pow(1-a, y.p) + math.Pow(1-b, y.p)
+	return math.Max(minDegree, 1-math.Pow(sum, 1/y.p))
+}
+
+func (y *yagerNorm) SNorm(a, b float64) float64 {
+	sum := math.Pow(a, y.p) + math.Pow(b, y.p)
+	return math.Min(maxDegree, math.Pow(sum, 1/y.p))
+}
+
+func (y *yagerNorm) String() string {
+	return fmt.Sprintf("%s(p: %f)", YagerNorm, y.p)
+}
@@ -0,0 +1,93 @@
+package number
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const DefaultBellSlope = 2.0
+
+// generalizedBellFuzzyNum has a flatter top and steeper flanks than a gaussian as its slope grows.
+type generalizedBellFuzzyNum struct {
+	center    float64
+	halfWidth float64
+	slope     float64
+}
+
+func GBFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, gbfnFromCluster)
+}
+
+func (g *generalizedBellFuzzyNum) MembershipDegree(x float64) float64 {
+	return 1 / (1 + math.Pow(math.Abs((x-g.center)/g.halfWidth), 2*g.slope))
+}
+
+func (g *generalizedBellFuzzyNum) String() string {
+	return fmt.Sprintf("center: %f, half width: %f, slope: %f", g.center, g.halfWidth, g.slope)
+}
+
+func (g *generalizedBellFuzzyNum) Type() string {
+	return GeneralizedBellFuzzyNum
+}
+
+func (g *generalizedBellFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"center": g.center, "halfWidth": g.halfWidth, "slope": g.slope}
+}
+
+func (g *generalizedBellFuzzyNum) validate() error {
+	if g.halfWidth <= 0 {
+		return fmt.Errorf("GBFN half width must be positive, got %f", g.halfWidth)
+	}
+
+	if g.slope <= 0 {
+		return fmt.Errorf("GBFN slope must be positive, got %f", g.slope)
+	}
+
+	return nil
+}
+
+// gbfnFromCluster makes the bell cross 0.5 at the bounds of the cluster support.
+func gbfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting GBFN bounds: %w", err)
+	}
+
+	center, err := clusterCenter(leftBound, rightBound)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting GBFN center: %w", err)
+	}
+
+	width, err := clusterWidth(leftBound, rightBound)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting GBFN width: %w", err)
+	}
+
+	return newGeneralizedBellFuzzyNum(center, width/2, DefaultBellSlope)
+}
+
+func newGeneralizedBellFuzzyNum(center, halfWidth, slope float64) (FuzzyNum, error) {
+	g := &generalizedBellFuzzyNum{
+		center:    center,
+		halfWidth: halfWidth,
+		slope:     slope,
+	}
+
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func gbfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "center", "halfWidth", "slope")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding GBFN: %s", err)
+	}
+
+	return newGeneralizedBellFuzzyNum(values[0], values[1], values[2])
+}
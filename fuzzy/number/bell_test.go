@@ -0,0 +1,34 @@
+package number
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGBFNCrossesHalfAtSupportBounds(t *testing.T) {
+	points := clusterPoints([]float64{1, 3}, []float64{1, 1})
+
+	fuzzyNum, err := gbfnFromCluster(&weightedFitter{}, points, clusterCentroid(2), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The weighted support of the cluster is [1, 3].
+	for x, want := range map[float64]float64{1: 0.5, 2: 1, 3: 0.5} {
+		if got := fuzzyNum.MembershipDegree(x); math.Abs(got-want) > fittingTolerance {
+			t.Errorf("got membership degree %f of %f, want %f", got, x, want)
+		}
+	}
+}
+
+func TestShoulderIsNoPersistedFuzzyNumType(t *testing.T) {
+	for _, fuzzyNumType := range FuzzyNumTypes() {
+		if _, ok := fuzzyNumDecoders[fuzzyNumType]; !ok {
+			t.Errorf("fuzzy number type %s has no decoder", fuzzyNumType)
+		}
+
+		if fuzzyNumType == ShoulderFuzzyNum {
+			t.Errorf("%s is listed as a fuzzy number type", ShoulderFuzzyNum)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package number
+
+import "github.com/IvanHristov98/postato/cluster"
+
+// Diagnostics records decisions taken while generating a rule set.
+type Diagnostics struct {
+	ClusterCountSelections []*ClusterCountSelection `json:"clusterCountSelections,omitempty"`
+	BoundsFallbacks        []*BoundsFallback        `json:"boundsFallbacks,omitempty"`
+	EmptyClusterRecoveries []*EmptyClusterRecovery  `json:"emptyClusterRecoveries,omitempty"`
+}
+
+// ClusterCountSelection records the validity scores of the searched cluster counts.
+type ClusterCountSelection struct {
+	// Activity is empty when all points are clustered together.
+	Activity      string          `json:"activity,omitempty"`
+	ValidityIndex string          `json:"validityIndex"`
+	Scores        map[int]float64 `json:"scores"`
+	ClusterCount  int             `json:"clusterCount"`
+	// TooFewPoints tells the activity had fewer points than the min cluster count so no count was searched
+	// and ClusterCount fell back to the fixed cluster count capped by the point count.
+	TooFewPoints bool `json:"tooFewPoints,omitempty"`
+}
+
+// BoundsFallback records a cluster dimension whose fuzzy number was replaced by the fallback policy.
+type BoundsFallback struct {
+	Activity   string `json:"activity,omitempty"`
+	ClusterIdx int    `json:"clusterIdx"`
+	Dim        int    `json:"dim"`
+	Policy     string `json:"policy"`
+	Cause      string `json:"cause"`
+}
+
+// EmptyClusterRecovery tells which activity a recovery of the kept k-means restart belongs to.
+type EmptyClusterRecovery struct {
+	Activity string `json:"activity,omitempty"`
+	cluster.EmptyClusterRecovery
+}
+
+func newDiagnostics() *Diagnostics {
+	return &Diagnostics{
+		ClusterCountSelections: []*ClusterCountSelection{},
+		BoundsFallbacks:        []*BoundsFallback{},
+		EmptyClusterRecoveries: []*EmptyClusterRecovery{},
+	}
+}
+
+func (d *Diagnostics) addEmptyClusterRecoveries(activity string, superCluster cluster.FuzzySuperCluster) {
+	reporter, ok := superCluster.(cluster.EmptyClusterReporter)
+	if !ok {
+		return
+	}
+
+	for _, recovery := range reporter.EmptyClusterRecoveries() {
+		d.EmptyClusterRecoveries = append(d.EmptyClusterRecoveries, &EmptyClusterRecovery{
+			Activity:             activity,
+			EmptyClusterRecovery: *recovery,
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package number
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+func TestEmptyClusterRecoveryFlattensIntoJSON(t *testing.T) {
+	recovery := &EmptyClusterRecovery{
+		Activity:             "walking",
+		EmptyClusterRecovery: cluster.EmptyClusterRecovery{Restart: 1, Iteration: 2, ClusterIdx: 3, Strategy: cluster.DropClusterStrategy},
+	}
+
+	data, err := json.Marshal(recovery)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := `{"activity":"walking","restart":1,"iteration":2,"clusterIdx":3,"strategy":"drop"}`
+
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,34 @@
+package number
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptySide means no cluster point lies on one side of the centroid.
+	ErrEmptySide = errors.New("No points on a side of the centroid")
+	// ErrTooFewPoints means too few cluster points are viable to fit bounds.
+	ErrTooFewPoints = errors.New("Too few viable points")
+	// ErrZeroSpread means all viable cluster points share the same coordinate.
+	ErrZeroSpread = errors.New("Zero spread of points")
+	// ErrInvertedBounds means a fitted left bound isn't less than the right one.
+	ErrInvertedBounds = errors.New("Left bound greater than or equal to right bound")
+)
+
+// BoundsError tells which cluster and dimension couldn't get a fuzzy number and why.
+type BoundsError struct {
+	// Activity is the label of the cluster which may be empty for unlabeled clusters.
+	Activity   string
+	ClusterIdx int
+	Dim        int
+	Cause      error
+}
+
+func (e *BoundsError) Error() string {
+	return fmt.Sprintf("Error fitting cluster %d of activity %q on dim %d: %s", e.ClusterIdx, e.Activity, e.Dim, e.Cause)
+}
+
+func (e *BoundsError) Unwrap() error {
+	return e.Cause
+}
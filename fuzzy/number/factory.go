@@ -1,18 +1,62 @@
 package number
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/IvanHristov98/postato/cluster"
 )
 
-func NewFuzzyRuleSet(fuzzyNumType string, points []*cluster.FuzzyPoint) (FuzzyRuleSet, error) {
+// FuzzyNumTypes lists the fuzzy number types a rule set may hold.
+func FuzzyNumTypes() []string {
+	return []string{
+		GaussianFuzzyNum,
+		TriangularFuzzyNum,
+		TrapezoidalFuzzyNum,
+		TwoSidedGaussianFuzzyNum,
+		GeneralizedBellFuzzyNum,
+		SigmoidFuzzyNum,
+		DiffSigmoidFuzzyNum,
+		PiFuzzyNum,
+		SShapedFuzzyNum,
+		ZShapedFuzzyNum,
+		IntervalType2GaussianFuzzyNum,
+	}
+}
+
+// RuleSetTypes lists what NewFuzzyRuleSet can build. Besides the fuzzy number types it offers
+// ShoulderFuzzyNum which mixes several of them.
+func RuleSetTypes() []string {
+	return append(FuzzyNumTypes(), ShoulderFuzzyNum)
+}
+
+func NewFuzzyRuleSet(ctx context.Context, fuzzyNumType string, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
 	switch fuzzyNumType {
 	case GaussianFuzzyNum:
-		return GFNRuleSet(points)
+		return GFNRuleSet(ctx, points, opts)
 	case TriangularFuzzyNum:
-		return TFNRuleSet(points)
+		return TFNRuleSet(ctx, points, opts)
+	case TrapezoidalFuzzyNum:
+		return TrFNRuleSet(ctx, points, opts)
+	case TwoSidedGaussianFuzzyNum:
+		return TSGFNRuleSet(ctx, points, opts)
+	case GeneralizedBellFuzzyNum:
+		return GBFNRuleSet(ctx, points, opts)
+	case SigmoidFuzzyNum:
+		return SFNRuleSet(ctx, points, opts)
+	case DiffSigmoidFuzzyNum:
+		return DSFNRuleSet(ctx, points, opts)
+	case PiFuzzyNum:
+		return PFNRuleSet(ctx, points, opts)
+	case SShapedFuzzyNum:
+		return SSFNRuleSet(ctx, points, opts)
+	case ZShapedFuzzyNum:
+		return ZSFNRuleSet(ctx, points, opts)
+	case ShoulderFuzzyNum:
+		return ShoulderRuleSet(ctx, points, opts)
+	case IntervalType2GaussianFuzzyNum:
+		return IT2GFNRuleSet(ctx, points, opts)
 	default:
-		return nil, fmt.Errorf("Invalid fuzzy num type provided %s", fuzzyNumType)
+		return nil, nil, fmt.Errorf("Invalid fuzzy num type provided %s", fuzzyNumType)
 	}
 }
@@ -0,0 +1,138 @@
+package number
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const (
+	// FailFallback aborts rule generation with a BoundsError.
+	FailFallback = "fail"
+	// SkipDimensionFallback leaves the dimension out of the rule by giving it a universal fuzzy number.
+	SkipDimensionFallback = "skip-dimension"
+	// GlobalRangeFallback fits the fuzzy number to the range of all clustered points on the dimension.
+	GlobalRangeFallback = "global-range"
+	// GlobalStdDevFallback fits the fuzzy number to the centroid plus and minus the standard deviation of all clustered points.
+	GlobalStdDevFallback = "global-std"
+)
+
+func BoundsFallbackPolicies() []string {
+	return []string{FailFallback, SkipDimensionFallback, GlobalRangeFallback, GlobalStdDevFallback}
+}
+
+func validateBoundsFallback(policy string) error {
+	for _, knownPolicy := range BoundsFallbackPolicies() {
+		if policy == knownPolicy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid bounds fallback policy %s", policy)
+}
+
+// fallbackFuzzyNum replaces the fuzzy number of a cluster dimension which couldn't be fitted according to policy.
+func fallbackFuzzyNum(policy string, converter superClusterToFNConverter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int, boundsErr *BoundsError) (FuzzyNum, error) {
+	var fitter boundsFitter
+
+	switch policy {
+	case SkipDimensionFallback:
+		return newUniversalFuzzyNum(), nil
+	case GlobalRangeFallback:
+		fitter = newFixedFitter(globalRange(points, dim))
+	case GlobalStdDevFallback:
+		stdDev := globalStdDev(points, dim)
+		fitter = newFixedFitter(centroid.Coords[dim]-stdDev, centroid.Coords[dim]+stdDev)
+	default:
+		return nil, boundsErr
+	}
+
+	fuzzyNum, err := converter(fitter, points, centroid, dim)
+	if err != nil {
+		boundsErr.Cause = fmt.Errorf("%w; %s fallback failed too: %s", boundsErr.Cause, policy, err)
+		return nil, boundsErr
+	}
+
+	return fuzzyNum, nil
+}
+
+func globalRange(points []*cluster.FuzzyPoint, dim int) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+
+	for _, point := range points {
+		low = math.Min(low, point.Coords[dim])
+		high = math.Max(high, point.Coords[dim])
+	}
+
+	return low, high
+}
+
+func globalStdDev(points []*cluster.FuzzyPoint, dim int) float64 {
+	cumCoord := 0.0
+
+	for _, point := range points {
+		cumCoord += point.Coords[dim]
+	}
+
+	mean := cumCoord / float64(len(points))
+	cumSquaredDev := 0.0
+
+	for _, point := range points {
+		cumSquaredDev += math.Pow(point.Coords[dim]-mean, 2)
+	}
+
+	return math.Sqrt(cumSquaredDev / float64(len(points)))
+}
+
+// fixedFitter returns the same support for every cluster. Its core shrinks to the centroid.
+type fixedFitter struct {
+	leftBound  float64
+	rightBound float64
+}
+
+func newFixedFitter(leftBound, rightBound float64) boundsFitter {
+	return &fixedFitter{
+		leftBound:  leftBound,
+		rightBound: rightBound,
+	}
+}
+
+func (f *fixedFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	if _, err := clusterWidth(f.leftBound, f.rightBound); err != nil {
+		return 0, 0, err
+	}
+
+	return f.leftBound, f.rightBound, nil
+}
+
+func (f *fixedFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return centroid.Coords[dim], centroid.Coords[dim], nil
+}
+
+// universalFuzzyNum fully contains every value so a rule holding it doesn't depend on its dimension.
+type universalFuzzyNum struct{}
+
+func newUniversalFuzzyNum() FuzzyNum {
+	return &universalFuzzyNum{}
+}
+
+func (u *universalFuzzyNum) MembershipDegree(x float64) float64 {
+	return 1.0
+}
+
+func (u *universalFuzzyNum) String() string {
+	return "universal"
+}
+
+func (u *universalFuzzyNum) Type() string {
+	return UniversalFuzzyNum
+}
+
+func (u *universalFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{}
+}
+
+func ufnFromParams(params map[string]float64) (FuzzyNum, error) {
+	return newUniversalFuzzyNum(), nil
+}
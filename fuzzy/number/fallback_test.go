@@ -0,0 +1,96 @@
+package number
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func newTestBoundsError(cause error) *BoundsError {
+	return &BoundsError{Activity: "walking", ClusterIdx: 0, Dim: 0, Cause: cause}
+}
+
+func TestSkipDimensionFallbackSurvivesPersistence(t *testing.T) {
+	points := clusterPoints([]float64{1, 2, 6}, []float64{1, 1, 1})
+
+	fuzzyNum, err := fallbackFuzzyNum(SkipDimensionFallback, tfnFromCluster, points, clusterCentroid(2), 0, newTestBoundsError(ErrEmptySide))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	ruleSet := FuzzyRuleSet{"walking": {{fuzzyNum, newGaussianFuzzyNum(0, 1)}}}
+
+	if err := Save(path, ruleSet); err != nil {
+		t.Fatalf("unexpected save error: %s", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected load error: %s", err)
+	}
+
+	universal := loaded["walking"][0][0]
+
+	if universal.Type() != UniversalFuzzyNum {
+		t.Fatalf("got fuzzy number type %s, want %s", universal.Type(), UniversalFuzzyNum)
+	}
+
+	for _, x := range []float64{-1e9, 0, 1e9} {
+		if universal.MembershipDegree(x) != 1 {
+			t.Errorf("got membership degree %f of %f, want 1", universal.MembershipDegree(x), x)
+		}
+	}
+}
+
+func TestGlobalFallbacksFitAllPoints(t *testing.T) {
+	points := clusterPoints([]float64{1, 2, 6}, []float64{1, 1, 1})
+	// The coordinates have mean 3 and population variance 14/3.
+	stdDev := math.Sqrt(14.0 / 3)
+
+	tests := []struct {
+		policy    string
+		wantLeft  float64
+		wantRight float64
+	}{
+		{GlobalRangeFallback, 1, 6},
+		{GlobalStdDevFallback, 2 - stdDev, 2 + stdDev},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			fuzzyNum, err := fallbackFuzzyNum(tt.policy, tfnFromCluster, points, clusterCentroid(2), 0, newTestBoundsError(ErrEmptySide))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			params := fuzzyNum.Params()
+			assertBounds(t, tt.wantLeft, tt.wantRight, params["left"], params["right"])
+		})
+	}
+}
+
+func TestFailedFallbackKeepsBoundsError(t *testing.T) {
+	// Coincident points leave the global range empty too.
+	points := clusterPoints([]float64{2, 2}, []float64{1, 1})
+
+	for _, policy := range []string{FailFallback, GlobalRangeFallback, GlobalStdDevFallback} {
+		t.Run(policy, func(t *testing.T) {
+			_, err := fallbackFuzzyNum(policy, tfnFromCluster, points, clusterCentroid(2), 0, newTestBoundsError(ErrEmptySide))
+
+			var boundsErr *BoundsError
+			if !errors.As(err, &boundsErr) {
+				t.Fatalf("got error %v, want a BoundsError", err)
+			}
+
+			if boundsErr.Activity != "walking" || boundsErr.ClusterIdx != 0 || boundsErr.Dim != 0 {
+				t.Errorf("got bounds error %v of another cluster dimension", boundsErr)
+			}
+
+			if !errors.Is(err, ErrEmptySide) {
+				t.Errorf("got error %v, want it to match %v", err, ErrEmptySide)
+			}
+		})
+	}
+}
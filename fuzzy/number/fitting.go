@@ -0,0 +1,401 @@
+package number
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const (
+	// AveragedFitting averages the coordinates of the cluster points on each side of the centroid.
+	AveragedFitting = "averaged"
+	// WeightedFitting spans the membership-weighted mean plus and minus the weighted standard deviation.
+	WeightedFitting = "weighted"
+	// QuantileFitting spans membership-weighted quantiles of the cluster points.
+	QuantileFitting = "quantile"
+	// KDEFitting spans the region around the mode where a membership-weighted kernel density estimate stays high.
+	KDEFitting = "kde"
+	// EllipsoidFitting projects the covariance ellipsoid of each Gustafson-Kessel cluster onto the axes.
+	EllipsoidFitting = "ellipsoid"
+
+	WeightedCoreStdDevRatio = 0.5
+	SupportQuantile         = 0.1
+	CoreQuantile            = 0.25
+	// A gaussian falls to about 0.6 of its peak one standard deviation away from its mean.
+	KDESupportDensityRatio = 0.6
+	KDECoreDensityRatio    = 0.9
+	KDEGridSize            = 256
+	// KDEGridPadding is the bandwidth count the grid extends beyond the outermost points.
+	KDEGridPadding = 3.0
+	// EllipsoidCoreRatio is the share of the projected ellipsoid radius spanned by the core.
+	EllipsoidCoreRatio = 0.5
+)
+
+// boundsFitter fits the extent of a cluster on a single dimension.
+type boundsFitter interface {
+	// support returns the bounds of the typical points of the cluster.
+	support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error)
+	// core returns the bounds of the most typical points of the cluster.
+	// Both lie within the support unless the cluster is skewed beyond its centroid.
+	core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error)
+}
+
+func FittingStrategies() []string {
+	return []string{AveragedFitting, WeightedFitting, QuantileFitting, KDEFitting, EllipsoidFitting}
+}
+
+// DefaultBoundsFitting projects the ellipsoids of Gustafson-Kessel clusters and averages bounds otherwise.
+func DefaultBoundsFitting(algorithm string) string {
+	if algorithm == GustafsonKesselClustering {
+		return EllipsoidFitting
+	}
+
+	return AveragedFitting
+}
+
+func validateBoundsFitting(name, algorithm string) error {
+	if name == EllipsoidFitting && algorithm != GustafsonKesselClustering {
+		return fmt.Errorf("Fitting strategy %s requires the %s algorithm", EllipsoidFitting, GustafsonKesselClustering)
+	}
+
+	for _, strategy := range FittingStrategies() {
+		if name == strategy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid fitting strategy %s", name)
+}
+
+func newBoundsFitter(name string, superCluster cluster.FuzzySuperCluster) (boundsFitter, error) {
+	switch name {
+	case AveragedFitting:
+		return &averagedFitter{}, nil
+	case WeightedFitting:
+		return &weightedFitter{}, nil
+	case QuantileFitting:
+		return &quantileFitter{}, nil
+	case KDEFitting:
+		return &kdeFitter{}, nil
+	case EllipsoidFitting:
+		covarianceSuperCluster, ok := superCluster.(cluster.CovarianceSuperCluster)
+		if !ok {
+			return nil, fmt.Errorf("Fitting strategy %s requires clusters with covariance matrices", EllipsoidFitting)
+		}
+
+		return &ellipsoidFitter{superCluster: covarianceSuperCluster}, nil
+	default:
+		return nil, fmt.Errorf("Invalid fitting strategy %s", name)
+	}
+}
+
+type averagedFitter struct{}
+
+func (a *averagedFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	left, right, leftCnt, rightCnt := sideAverages(points, centroid, dim, MinViableMembershipDegree)
+
+	if leftCnt == 0 {
+		return 0, 0, fmt.Errorf("%w: none with membership degree of at least %f left of it", ErrEmptySide, MinViableMembershipDegree)
+	}
+
+	if rightCnt == 0 {
+		return 0, 0, fmt.Errorf("%w: none with membership degree of at least %f right of it", ErrEmptySide, MinViableMembershipDegree)
+	}
+
+	return left, right, nil
+}
+
+// core shrinks to the centroid on a side without typical points.
+func (a *averagedFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	left, right, leftCnt, rightCnt := sideAverages(points, centroid, dim, PlateauMembershipDegree)
+
+	if leftCnt == 0 {
+		left = centroid.Coords[dim]
+	}
+
+	if rightCnt == 0 {
+		right = centroid.Coords[dim]
+	}
+
+	return left, right, nil
+}
+
+// sideAverages averages the coordinates on each side of the centroid of points with at least the given membership degree.
+// Each side is averaged on its own so a cluster with points on a single side still keeps that side.
+func sideAverages(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int, minMembershipDegree float64) (float64, float64, int, int) {
+	cumMin := 0.0
+	minCnt := 0
+	cumMax := 0.0
+	maxCnt := 0
+
+	centroidCoord := centroid.Coords[dim]
+
+	for _, point := range points {
+		// The best fit cluster index of a centroid should always be the cluster it belongs to.
+		membershipDegree := point.MembershipDegree(centroid.BestFitClusterIdx)
+		coord := point.Coords[dim]
+
+		if membershipDegree < minMembershipDegree {
+			continue
+		}
+
+		// Less than centroid center means min.
+		if membershipDegree+BoundWidth > minMembershipDegree && coord < centroidCoord {
+			cumMin += point.Coords[dim]
+			minCnt++
+		}
+
+		// More than centroid center means max.
+		if membershipDegree+BoundWidth > minMembershipDegree && coord > centroidCoord {
+			cumMax += point.Coords[dim]
+			maxCnt++
+		}
+	}
+
+	return sideAverage(cumMin, minCnt), sideAverage(cumMax, maxCnt), minCnt, maxCnt
+}
+
+// sideAverage is 0 for a side without points and leaves it to the caller to tell it apart by count.
+func sideAverage(cumCoord float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return cumCoord / float64(count)
+}
+
+type weightedFitter struct{}
+
+func (w *weightedFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return w.bounds(points, centroid, dim, 1)
+}
+
+func (w *weightedFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return w.bounds(points, centroid, dim, WeightedCoreStdDevRatio)
+}
+
+func (w *weightedFitter) bounds(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int, stdDevRatio float64) (float64, float64, error) {
+	coords, weights := viableCoords(points, centroid, dim)
+
+	cumWeight := 0.0
+	cumCoord := 0.0
+
+	for i, coord := range coords {
+		cumWeight += weights[i]
+		cumCoord += weights[i] * coord
+	}
+
+	if cumWeight == 0 {
+		return 0, 0, fmt.Errorf("%w: none with membership degree of at least %f", ErrTooFewPoints, MinViableMembershipDegree)
+	}
+
+	mean := cumCoord / cumWeight
+	cumSquaredDev := 0.0
+
+	for i, coord := range coords {
+		cumSquaredDev += weights[i] * math.Pow(coord-mean, 2)
+	}
+
+	stdDev := math.Sqrt(cumSquaredDev / cumWeight)
+
+	if stdDev == 0 {
+		return 0, 0, fmt.Errorf("%w: weighted variance is zero", ErrZeroSpread)
+	}
+
+	return mean - stdDevRatio*stdDev, mean + stdDevRatio*stdDev, nil
+}
+
+type quantileFitter struct{}
+
+func (q *quantileFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return q.bounds(points, centroid, dim, SupportQuantile)
+}
+
+func (q *quantileFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return q.bounds(points, centroid, dim, CoreQuantile)
+}
+
+// bounds returns the weighted quantiles of the given level and of its complement.
+func (q *quantileFitter) bounds(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int, level float64) (float64, float64, error) {
+	coords, weights := viableCoords(points, centroid, dim)
+
+	if len(coords) == 0 {
+		return 0, 0, fmt.Errorf("%w: none with membership degree of at least %f", ErrTooFewPoints, MinViableMembershipDegree)
+	}
+
+	order := make([]int, len(coords))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return coords[order[i]] < coords[order[j]] })
+
+	cumWeights := make([]float64, len(order))
+	cumWeight := 0.0
+
+	for i, idx := range order {
+		cumWeight += weights[idx]
+		cumWeights[i] = cumWeight
+	}
+
+	quantile := func(level float64) float64 {
+		i := sort.SearchFloat64s(cumWeights, level*cumWeight)
+		if i == len(order) {
+			i--
+		}
+
+		return coords[order[i]]
+	}
+
+	lower, upper := quantile(level), quantile(1-level)
+
+	if lower >= upper {
+		return 0, 0, fmt.Errorf("%w: quantiles %f and %f coincide at %f", ErrZeroSpread, level, 1-level, lower)
+	}
+
+	return lower, upper, nil
+}
+
+type kdeFitter struct{}
+
+func (k *kdeFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return k.bounds(points, centroid, dim, KDESupportDensityRatio)
+}
+
+func (k *kdeFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return k.bounds(points, centroid, dim, KDECoreDensityRatio)
+}
+
+// bounds walks from the mode of the density estimate to both sides until the density drops below ratio of its peak.
+func (k *kdeFitter) bounds(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int, ratio float64) (float64, float64, error) {
+	coords, weights := viableCoords(points, centroid, dim)
+
+	bandwidth, err := silvermanBandwidth(coords, weights)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	low, high := coords[0], coords[0]
+
+	for _, coord := range coords {
+		low = math.Min(low, coord)
+		high = math.Max(high, coord)
+	}
+
+	low -= KDEGridPadding * bandwidth
+	high += KDEGridPadding * bandwidth
+	step := (high - low) / float64(KDEGridSize-1)
+
+	densities := make([]float64, KDEGridSize)
+	modeIdx := 0
+
+	for i := range densities {
+		x := low + float64(i)*step
+
+		for j, coord := range coords {
+			densities[i] += weights[j] * math.Exp(-math.Pow((x-coord)/bandwidth, 2)/2)
+		}
+
+		if densities[i] > densities[modeIdx] {
+			modeIdx = i
+		}
+	}
+
+	threshold := ratio * densities[modeIdx]
+	leftIdx, rightIdx := modeIdx, modeIdx
+
+	for leftIdx > 0 && densities[leftIdx-1] >= threshold {
+		leftIdx--
+	}
+
+	for rightIdx < KDEGridSize-1 && densities[rightIdx+1] >= threshold {
+		rightIdx++
+	}
+
+	if leftIdx == rightIdx {
+		return 0, 0, fmt.Errorf("%w: density estimate is too narrow", ErrZeroSpread)
+	}
+
+	return low + float64(leftIdx)*step, low + float64(rightIdx)*step, nil
+}
+
+// silvermanBandwidth applies Silverman's rule of thumb to the weighted coordinates using their effective sample size.
+func silvermanBandwidth(coords, weights []float64) (float64, error) {
+	cumWeight := 0.0
+	cumSquaredWeight := 0.0
+	cumCoord := 0.0
+
+	for i, coord := range coords {
+		cumWeight += weights[i]
+		cumSquaredWeight += weights[i] * weights[i]
+		cumCoord += weights[i] * coord
+	}
+
+	if len(coords) < 2 || cumWeight == 0 {
+		return 0, fmt.Errorf("%w: density estimation needs at least 2 with membership degree of at least %f", ErrTooFewPoints, MinViableMembershipDegree)
+	}
+
+	mean := cumCoord / cumWeight
+	cumSquaredDev := 0.0
+
+	for i, coord := range coords {
+		cumSquaredDev += weights[i] * math.Pow(coord-mean, 2)
+	}
+
+	stdDev := math.Sqrt(cumSquaredDev / cumWeight)
+
+	if stdDev == 0 {
+		return 0, fmt.Errorf("%w: weighted variance is zero", ErrZeroSpread)
+	}
+
+	effectiveCount := cumWeight * cumWeight / cumSquaredWeight
+
+	return 1.06 * stdDev * math.Pow(effectiveCount, -0.2), nil
+}
+
+// ellipsoidFitter projects the ellipsoid one standard deviation around the centroid onto the axis.
+// Unlike the other fitters it ignores the points since their spread is already captured by the cluster covariance.
+type ellipsoidFitter struct {
+	superCluster cluster.CovarianceSuperCluster
+}
+
+func (e *ellipsoidFitter) support(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return e.projection(centroid, dim, 1)
+}
+
+func (e *ellipsoidFitter) core(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, error) {
+	return e.projection(centroid, dim, EllipsoidCoreRatio)
+}
+
+func (e *ellipsoidFitter) projection(centroid *cluster.FuzzyPoint, dim int, ratio float64) (float64, float64, error) {
+	covariance := e.superCluster.Covariance(centroid.BestFitClusterIdx)
+	stdDev := math.Sqrt(covariance[dim][dim])
+
+	if stdDev == 0 || math.IsNaN(stdDev) {
+		return 0.0, 0.0, fmt.Errorf("%w: ellipsoid projects onto a single point", ErrZeroSpread)
+	}
+
+	return centroid.Coords[dim] - ratio*stdDev, centroid.Coords[dim] + ratio*stdDev, nil
+}
+
+// viableCoords returns the coordinates of the points with at least MinViableMembershipDegree weighted by it.
+func viableCoords(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) ([]float64, []float64) {
+	coords := []float64{}
+	weights := []float64{}
+
+	for _, point := range points {
+		membershipDegree := point.MembershipDegree(centroid.BestFitClusterIdx)
+
+		if membershipDegree < MinViableMembershipDegree {
+			continue
+		}
+
+		coords = append(coords, point.Coords[dim])
+		weights = append(weights, membershipDegree)
+	}
+
+	return coords, weights
+}
@@ -0,0 +1,282 @@
+package number
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const fittingTolerance = 1e-9
+
+// clusterPoints returns single dimension points of cluster 0 with the given coordinates and membership degrees.
+func clusterPoints(coords, membershipDegrees []float64) []*cluster.FuzzyPoint {
+	points := make([]*cluster.FuzzyPoint, len(coords))
+
+	for i, coord := range coords {
+		points[i] = cluster.NewFuzzyPoint([]float64{coord}, "")
+		points[i].BestFitClusterIdx = 0
+		points[i].SetMembershipDegree(0, membershipDegrees[i])
+	}
+
+	return points
+}
+
+func clusterCentroid(coord float64) *cluster.FuzzyPoint {
+	centroid := cluster.NewFuzzyPoint([]float64{coord}, "")
+	centroid.BestFitClusterIdx = 0
+
+	return centroid
+}
+
+func assertBounds(t *testing.T, wantLeft, wantRight, left, right float64) {
+	t.Helper()
+
+	if math.Abs(left-wantLeft) > fittingTolerance || math.Abs(right-wantRight) > fittingTolerance {
+		t.Errorf("got bounds [%f, %f], want [%f, %f]", left, right, wantLeft, wantRight)
+	}
+}
+
+func TestAveragedFitterKeepsTheSideWithPoints(t *testing.T) {
+	// No point right of the centroid reaches the plateau membership degree.
+	points := clusterPoints([]float64{3, 4, 6}, []float64{0.9, 0.8, 0.3})
+	centroid := clusterCentroid(5)
+	fitter := &averagedFitter{}
+
+	left, right, err := fitter.support(points, centroid, 0)
+	if err != nil {
+		t.Fatalf("unexpected support error: %s", err)
+	}
+
+	assertBounds(t, 3.5, 6, left, right)
+
+	left, right, err = fitter.core(points, centroid, 0)
+	if err != nil {
+		t.Fatalf("unexpected core error: %s", err)
+	}
+
+	assertBounds(t, 3.5, 5, left, right)
+
+	left, innerLeft, innerRight, right, err := clusterPlateau(fitter, points, centroid, 0)
+	if err != nil {
+		t.Fatalf("unexpected plateau error: %s", err)
+	}
+
+	assertBounds(t, 3.5, 6, left, right)
+	assertBounds(t, 3.5, 5, innerLeft, innerRight)
+}
+
+func TestAveragedFitterFailsOnEmptySupportSide(t *testing.T) {
+	points := clusterPoints([]float64{3, 4}, []float64{0.9, 0.8})
+
+	if _, _, err := (&averagedFitter{}).support(points, clusterCentroid(5), 0); !errors.Is(err, ErrEmptySide) {
+		t.Errorf("got error %v, want %v", err, ErrEmptySide)
+	}
+}
+
+func TestWeightedFitter(t *testing.T) {
+	tests := []struct {
+		name              string
+		coords            []float64
+		membershipDegrees []float64
+		wantSupport       [2]float64
+		wantCore          [2]float64
+	}{
+		{
+			name:              "equal weights",
+			coords:            []float64{1, 3},
+			membershipDegrees: []float64{1, 1},
+			wantSupport:       [2]float64{1, 3},
+			wantCore:          [2]float64{1.5, 2.5},
+		},
+		{
+			// Mean 1 and variance (1*1 + 0.5*4) / 1.5 = 2 while the point at 100 isn't viable.
+			name:              "unequal weights",
+			coords:            []float64{0, 3, 100},
+			membershipDegrees: []float64{1, 0.5, 0.01},
+			wantSupport:       [2]float64{1 - math.Sqrt2, 1 + math.Sqrt2},
+			wantCore:          [2]float64{1 - math.Sqrt2/2, 1 + math.Sqrt2/2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := clusterPoints(tt.coords, tt.membershipDegrees)
+			fitter := &weightedFitter{}
+
+			left, right, err := fitter.support(points, clusterCentroid(0), 0)
+			if err != nil {
+				t.Fatalf("unexpected support error: %s", err)
+			}
+
+			assertBounds(t, tt.wantSupport[0], tt.wantSupport[1], left, right)
+
+			left, right, err = fitter.core(points, clusterCentroid(0), 0)
+			if err != nil {
+				t.Fatalf("unexpected core error: %s", err)
+			}
+
+			assertBounds(t, tt.wantCore[0], tt.wantCore[1], left, right)
+		})
+	}
+}
+
+func TestQuantileFitter(t *testing.T) {
+	coords := []float64{}
+	membershipDegrees := []float64{}
+
+	for coord := 1.0; coord <= 10; coord++ {
+		coords = append(coords, coord)
+		membershipDegrees = append(membershipDegrees, 1)
+	}
+
+	points := clusterPoints(coords, membershipDegrees)
+	fitter := &quantileFitter{}
+
+	left, right, err := fitter.support(points, clusterCentroid(5), 0)
+	if err != nil {
+		t.Fatalf("unexpected support error: %s", err)
+	}
+
+	assertBounds(t, 1, 9, left, right)
+
+	left, right, err = fitter.core(points, clusterCentroid(5), 0)
+	if err != nil {
+		t.Fatalf("unexpected core error: %s", err)
+	}
+
+	assertBounds(t, 3, 8, left, right)
+}
+
+func TestSilvermanBandwidth(t *testing.T) {
+	// Standard deviation 1 and an effective sample size of 2 regardless of the weight scale.
+	want := 1.06 * math.Pow(2, -0.2)
+
+	for _, weights := range [][]float64{{1, 1}, {0.5, 0.5}} {
+		bandwidth, err := silvermanBandwidth([]float64{0, 2}, weights)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if math.Abs(bandwidth-want) > fittingTolerance {
+			t.Errorf("got bandwidth %f for weights %v, want %f", bandwidth, weights, want)
+		}
+	}
+}
+
+func TestKDEFitter(t *testing.T) {
+	points := clusterPoints([]float64{-1, 0, 1}, []float64{1, 1, 1})
+	bandwidth := 1.06 * math.Sqrt(2.0/3) * math.Pow(3, -0.2)
+	step := (2 + 2*KDEGridPadding*bandwidth) / (KDEGridSize - 1)
+	fitter := &kdeFitter{}
+
+	// The density of the three kernels falls to 60% and 90% of its peak at 0 at about ±1.3589 and ±0.7477.
+	tests := []struct {
+		name  string
+		fit   func([]*cluster.FuzzyPoint, *cluster.FuzzyPoint, int) (float64, float64, error)
+		bound float64
+	}{
+		{name: "support", fit: fitter.support, bound: 1.3588867},
+		{name: "core", fit: fitter.core, bound: 0.7476771},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right, err := tt.fit(points, clusterCentroid(0), 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// Bounds snap inwards to the grid.
+			if left < -tt.bound || left > -tt.bound+step || right > tt.bound || right < tt.bound-step {
+				t.Errorf("got bounds [%f, %f], want within a grid step of [%f, %f]", left, right, -tt.bound, tt.bound)
+			}
+		})
+	}
+}
+
+func TestFitterErrors(t *testing.T) {
+	tests := []struct {
+		name              string
+		fitter            boundsFitter
+		coords            []float64
+		membershipDegrees []float64
+		want              error
+	}{
+		{"weighted without viable points", &weightedFitter{}, []float64{1, 2}, []float64{0.01, 0.01}, ErrTooFewPoints},
+		{"weighted with coincident points", &weightedFitter{}, []float64{2, 2}, []float64{1, 0.5}, ErrZeroSpread},
+		{"quantile without viable points", &quantileFitter{}, []float64{1, 2}, []float64{0.01, 0.01}, ErrTooFewPoints},
+		{"quantile with a single point", &quantileFitter{}, []float64{2}, []float64{1}, ErrZeroSpread},
+		{"kde with a single point", &kdeFitter{}, []float64{2}, []float64{1}, ErrTooFewPoints},
+		{"kde with coincident points", &kdeFitter{}, []float64{2, 2}, []float64{1, 0.5}, ErrZeroSpread},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := clusterPoints(tt.coords, tt.membershipDegrees)
+
+			if _, _, err := tt.fitter.support(points, clusterCentroid(2), 0); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// covarianceSuperClusterStub only provides covariances and panics on any other super cluster method.
+type covarianceSuperClusterStub struct {
+	cluster.FuzzySuperCluster
+	covariances [][][]float64
+}
+
+func (c *covarianceSuperClusterStub) Covariance(clusterIdx int) [][]float64 {
+	return c.covariances[clusterIdx]
+}
+
+func TestEllipsoidFitterProjectsCovariance(t *testing.T) {
+	superCluster := &covarianceSuperClusterStub{covariances: [][][]float64{{{4, 1}, {1, 9}}}}
+
+	fitter, err := newBoundsFitter(EllipsoidFitting, superCluster)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	centroid := cluster.NewFuzzyPoint([]float64{1, 2}, "")
+	centroid.BestFitClusterIdx = 0
+
+	tests := []struct {
+		dim         int
+		wantSupport [2]float64
+		wantCore    [2]float64
+	}{
+		{dim: 0, wantSupport: [2]float64{-1, 3}, wantCore: [2]float64{0, 2}},
+		{dim: 1, wantSupport: [2]float64{-1, 5}, wantCore: [2]float64{0.5, 3.5}},
+	}
+
+	for _, tt := range tests {
+		left, right, err := fitter.support(nil, centroid, tt.dim)
+		if err != nil {
+			t.Fatalf("unexpected support error: %s", err)
+		}
+
+		assertBounds(t, tt.wantSupport[0], tt.wantSupport[1], left, right)
+
+		left, right, err = fitter.core(nil, centroid, tt.dim)
+		if err != nil {
+			t.Fatalf("unexpected core error: %s", err)
+		}
+
+		assertBounds(t, tt.wantCore[0], tt.wantCore[1], left, right)
+	}
+}
+
+func TestEllipsoidFitterNeedsCovariances(t *testing.T) {
+	superCluster, err := cluster.NewFuzzyCMeansSuperCluster(nil, 1, cluster.DefaultFuzzifier, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := newBoundsFitter(EllipsoidFitting, superCluster); err == nil {
+		t.Error("expected an error for clusters without covariances")
+	}
+}
@@ -1,6 +1,7 @@
 package number
 
 import (
+	"context"
 	"fmt"
 	"math"
 
@@ -12,8 +13,8 @@ type gaussianFuzzyNum struct {
 	stdDev float64
 }
 
-func GFNRuleSet(points []*cluster.FuzzyPoint) (FuzzyRuleSet, error) {
-	return fuzzyNumRuleSet(points, gfnFromCluster)
+func GFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, gfnFromCluster)
 }
 
 func (gfn *gaussianFuzzyNum) MembershipDegree(x float64) float64 {
@@ -26,17 +27,28 @@ func (gfn *gaussianFuzzyNum) String() string {
 	return fmt.Sprintf("mean: %f, std dev: %f", gfn.mean, gfn.stdDev)
 }
 
-func gfnFromCluster(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
-	leftBound, rightBound := clusterBounds(points, centroid, dim)
+func (gfn *gaussianFuzzyNum) Type() string {
+	return GaussianFuzzyNum
+}
+
+func (gfn *gaussianFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"mean": gfn.mean, "stdDev": gfn.stdDev}
+}
+
+func gfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting GFN bounds: %w", err)
+	}
 
 	mean, err := clusterCenter(leftBound, rightBound)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting GFN mean: %s", err)
+		return nil, fmt.Errorf("Error getting GFN mean: %w", err)
 	}
 
 	stdDev, err := clusterWidth(leftBound, rightBound)
 	if err != nil {
-		return nil, fmt.Errorf("Error obtain GFN standard deviation: %s", err)
+		return nil, fmt.Errorf("Error obtain GFN standard deviation: %w", err)
 	}
 
 	return newGaussianFuzzyNum(mean, stdDev), nil
@@ -48,3 +60,18 @@ func newGaussianFuzzyNum(mean float64, stdDev float64) FuzzyNum {
 		stdDev: stdDev,
 	}
 }
+
+func gfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "mean", "stdDev")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding GFN: %s", err)
+	}
+
+	mean, stdDev := values[0], values[1]
+
+	if stdDev <= 0 {
+		return nil, fmt.Errorf("GFN standard deviation must be positive, got %f", stdDev)
+	}
+
+	return newGaussianFuzzyNum(mean, stdDev), nil
+}
@@ -1,110 +1,277 @@
 package number
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
 
 	"github.com/IvanHristov98/postato/cluster"
 )
 
 const (
 	// TODO: Tweak values to improve accuracy.
-	MinViableMembershipDegree = 0.05
-	BoundWidth                = 0.02
-	OptimalClusterCount       = 3
-	ClusteringRestartCount    = 10
-	GaussianFuzzyNum          = "gaussian"
-	TriangularFuzzyNum        = "triangular"
+	MinViableMembershipDegree     = 0.05
+	BoundWidth                    = 0.02
+	OptimalClusterCount           = 3
+	ClusteringRestartCount        = 10
+	GaussianFuzzyNum              = "gaussian"
+	TriangularFuzzyNum            = "triangular"
+	TrapezoidalFuzzyNum           = "trapezoidal"
+	TwoSidedGaussianFuzzyNum      = "two-sided-gaussian"
+	GeneralizedBellFuzzyNum       = "bell"
+	SigmoidFuzzyNum               = "sigmoid"
+	DiffSigmoidFuzzyNum           = "diff-sigmoid"
+	PiFuzzyNum                    = "pi"
+	SShapedFuzzyNum               = "s-shaped"
+	ZShapedFuzzyNum               = "z-shaped"
+	IntervalType2GaussianFuzzyNum = "it2-gaussian"
+	// ShoulderFuzzyNum isn't a fuzzy number type of its own but picks Z-, pi- or S-shapes by cluster position.
+	ShoulderFuzzyNum = "shoulder"
+	// UniversalFuzzyNum only stands in for dimensions skipped by SkipDimensionFallback.
+	UniversalFuzzyNum = "universal"
+	// PlateauMembershipDegree is the least membership degree of the points spanning the plateau of a trapezoid.
+	PlateauMembershipDegree = 0.5
 )
 
 type FuzzyNum interface {
 	MembershipDegree(x float64) float64
 	String() string
+	Type() string
+	Params() map[string]float64
 }
 
+// FuzzyRule is the antecedent of a rule holding a fuzzy number per dimension.
 type FuzzyRule []FuzzyNum
 
-type FuzzyRuleSet map[string]FuzzyRule
+// FuzzyRuleSet maps each activity to the rules implying it. Rules of the same activity are OR-ed.
+type FuzzyRuleSet map[string][]FuzzyRule
 
-type superClusterToFNConverter func(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error)
+type superClusterToFNConverter func(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error)
+
+func fuzzyNumRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions, converter superClusterToFNConverter) (FuzzyRuleSet, *Diagnostics, error) {
+	if err := opts.validate(); err != nil {
+		return nil, nil, fmt.Errorf("Invalid rule set options: %s", err)
+	}
 
-func fuzzyNumRuleSet(points []*cluster.FuzzyPoint, converter superClusterToFNConverter) (FuzzyRuleSet, error) {
 	ruleSet := make(FuzzyRuleSet)
+	diagnostics := newDiagnostics()
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	if opts.Mode == GlobalClustering {
+		superCluster, err := buildSuperCluster(ctx, points, "", opts, rnd, diagnostics)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		diagnostics.addEmptyClusterRecoveries("", superCluster)
 
-	superCluster := cluster.NewKMeansSuperCluster(points, OptimalClusterCount)
-	superCluster.Adjust(ClusteringRestartCount)
+		if err := addClusterRules(ruleSet, superCluster, converter, opts, diagnostics); err != nil {
+			return nil, nil, err
+		}
+
+		return ruleSet, diagnostics, nil
+	}
 
+	activityPoints := groupByActivity(points)
+
+	for _, activity := range sortedActivities(activityPoints) {
+		superCluster, err := buildSuperCluster(ctx, activityPoints[activity], activity, opts, rnd, diagnostics)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error clustering activity %s: %w", activity, err)
+		}
+
+		diagnostics.addEmptyClusterRecoveries(activity, superCluster)
+
+		if err := addClusterRules(ruleSet, superCluster, converter, opts, diagnostics); err != nil {
+			return nil, nil, fmt.Errorf("Error generating rules for activity %s: %w", activity, err)
+		}
+	}
+
+	return ruleSet, diagnostics, nil
+}
+
+// buildSuperCluster fits the distance metric once so every searched cluster count and the validity index share it.
+func buildSuperCluster(ctx context.Context, points []*cluster.FuzzyPoint, activity string, opts *RuleSetOptions, rnd *rand.Rand, diagnostics *Diagnostics) (cluster.FuzzySuperCluster, error) {
+	metric, err := cluster.NewDistanceMetric(opts.DistanceMetric, points)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting distance metric: %s", err)
+	}
+
+	if !opts.searchesClusterCount(activity) {
+		return adjustedSuperCluster(ctx, points, opts.activityClusterCount(activity), metric, opts, rnd)
+	}
+
+	validityIndex, err := cluster.NewValidityIndex(opts.ValidityIndex, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &ClusterCountSelection{
+		Activity:      activity,
+		ValidityIndex: validityIndex.String(),
+		Scores:        make(map[int]float64),
+	}
+
+	if len(points) < opts.MinClusterCount {
+		selection.ClusterCount = int(math.Min(float64(len(points)), float64(opts.activityClusterCount(activity))))
+		selection.TooFewPoints = true
+
+		log.Printf("Activity %q has %d points, fewer than the min cluster count %d, so it gets %d clusters", activity, len(points), opts.MinClusterCount, selection.ClusterCount)
+		diagnostics.ClusterCountSelections = append(diagnostics.ClusterCountSelections, selection)
+
+		return adjustedSuperCluster(ctx, points, selection.ClusterCount, metric, opts, rnd)
+	}
+
+	var bestSuperCluster cluster.FuzzySuperCluster
+	bestScore := math.NaN()
+
+	for clusterCount := opts.MinClusterCount; clusterCount <= opts.MaxClusterCount && clusterCount <= len(points); clusterCount++ {
+		superCluster, err := adjustedSuperCluster(ctx, points, clusterCount, metric, opts, rnd)
+		if err != nil {
+			return nil, err
+		}
+
+		score := validityIndex.Score(superCluster)
+		log.Printf("Cluster count %d of activity %q has %s score %f", clusterCount, activity, validityIndex, score)
+
+		// Scores are stored in model metadata and JSON can't encode NaN.
+		if math.IsNaN(score) || math.IsInf(score, 0) {
+			continue
+		}
+
+		selection.Scores[clusterCount] = score
+
+		if bestSuperCluster == nil || validityIndex.Better(score, bestScore) {
+			bestSuperCluster = superCluster
+			bestScore = score
+			selection.ClusterCount = clusterCount
+		}
+	}
+
+	if bestSuperCluster == nil {
+		return nil, fmt.Errorf("No cluster count in [%d, %d] could be scored by %s", opts.MinClusterCount, opts.MaxClusterCount, validityIndex)
+	}
+
+	log.Printf("Selected cluster count %d of activity %q by %s", selection.ClusterCount, activity, validityIndex)
+	diagnostics.ClusterCountSelections = append(diagnostics.ClusterCountSelections, selection)
+
+	return bestSuperCluster, nil
+}
+
+func adjustedSuperCluster(ctx context.Context, points []*cluster.FuzzyPoint, clusterCount int, metric cluster.DistanceMetric, opts *RuleSetOptions, rnd *rand.Rand) (cluster.FuzzySuperCluster, error) {
+	if clusterCount > len(points) {
+		log.Printf("Reducing cluster count from %d to the %d available points", clusterCount, len(points))
+		clusterCount = len(points)
+	}
+
+	superCluster, err := newSuperCluster(points, clusterCount, metric, opts, rnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := superCluster.Adjust(ctx, opts.adjustOptions()); err != nil {
+		return nil, fmt.Errorf("Error clustering points: %w", err)
+	}
+
+	return superCluster, nil
+}
+
+func newSuperCluster(points []*cluster.FuzzyPoint, clusterCount int, metric cluster.DistanceMetric, opts *RuleSetOptions, rnd *rand.Rand) (cluster.FuzzySuperCluster, error) {
+	switch opts.Algorithm {
+	case FuzzyCMeansClustering:
+		return cluster.NewFuzzyCMeansSuperCluster(points, clusterCount, opts.Fuzzifier, metric, rnd)
+	case GustafsonKesselClustering:
+		return cluster.NewGustafsonKesselSuperCluster(points, clusterCount, opts.Fuzzifier, metric, rnd)
+	default:
+		return cluster.NewKMeansSuperCluster(points, clusterCount, opts.Parallelism, opts.EmptyClusterStrategy, metric, rnd)
+	}
+}
+
+// addClusterRules fits bounds per super cluster since some fitters read them off the clusters themselves.
+func addClusterRules(ruleSet FuzzyRuleSet, superCluster cluster.FuzzySuperCluster, converter superClusterToFNConverter, opts *RuleSetOptions, diagnostics *Diagnostics) error {
+	fitter, err := newBoundsFitter(opts.EffectiveBoundsFitting(), superCluster)
+	if err != nil {
+		return err
+	}
+
+	fallbackPolicy := opts.BoundsFallback
 	clusteredPoints := superCluster.ClusteredPoints()
 	centroids := superCluster.Centroids()
 	dimCount, err := superCluster.DimCount()
 	if err != nil {
-		return nil, fmt.Errorf("Error obtaining dimension count: %s", err)
+		return fmt.Errorf("Error obtaining dimension count: %s", err)
 	}
 
 	for _, centroid := range centroids {
 		rule := FuzzyRule{}
 
 		for dim := 0; dim < dimCount; dim++ {
-			gfn, err := converter(clusteredPoints, centroid, dim)
+			fuzzyNum, err := converter(fitter, clusteredPoints, centroid, dim)
+
 			if err != nil {
-				return nil, fmt.Errorf("Error obtaining GFN for cluster %d on dim %d: %s", centroid.BestFitClusterIdx, dim, err)
+				boundsErr := &BoundsError{
+					Activity:   centroid.Activity,
+					ClusterIdx: centroid.BestFitClusterIdx,
+					Dim:        dim,
+					Cause:      err,
+				}
+
+				if fuzzyNum, err = fallbackFuzzyNum(fallbackPolicy, converter, clusteredPoints, centroid, dim, boundsErr); err != nil {
+					return err
+				}
+
+				log.Printf("Falling back to %s: %s", fallbackPolicy, boundsErr)
+				diagnostics.BoundsFallbacks = append(diagnostics.BoundsFallbacks, &BoundsFallback{
+					Activity:   boundsErr.Activity,
+					ClusterIdx: boundsErr.ClusterIdx,
+					Dim:        boundsErr.Dim,
+					Policy:     fallbackPolicy,
+					Cause:      boundsErr.Cause.Error(),
+				})
 			}
 
-			rule = append(rule, gfn)
+			rule = append(rule, fuzzyNum)
 		}
 
-		ruleSet[centroid.Activity] = rule
+		ruleSet[centroid.Activity] = append(ruleSet[centroid.Activity], rule)
 	}
 
-	return ruleSet, nil
+	return nil
 }
 
-func clusterBounds(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64) {
-	cumMin := 0.0
-	minCnt := 0
-	cumMax := 0.0
-	maxCnt := 0
-
-	centroidCoord := centroid.Coords[dim]
+// groupByActivity skips unlabeled points since they can't imply any activity.
+func groupByActivity(points []*cluster.FuzzyPoint) map[string][]*cluster.FuzzyPoint {
+	activityPoints := make(map[string][]*cluster.FuzzyPoint)
 
 	for _, point := range points {
-		// The best fit cluster index of a centroid should always be the cluster it belongs to.
-		membershipDegree := point.MembershipDegree(centroid.BestFitClusterIdx)
-		coord := point.Coords[dim]
-
-		if membershipDegree < MinViableMembershipDegree {
+		if point.Activity == "" {
 			continue
 		}
 
-		// Less than centroid center means min.
-		if membershipDegree+BoundWidth > MinViableMembershipDegree && coord < centroidCoord {
-			cumMin += point.Coords[dim]
-			minCnt++
-		}
-
-		// More than centroid center means max.
-		if membershipDegree+BoundWidth > MinViableMembershipDegree && coord > centroidCoord {
-			cumMax += point.Coords[dim]
-			maxCnt++
-		}
+		activityPoints[point.Activity] = append(activityPoints[point.Activity], point)
 	}
 
-	if minCnt == 0 {
-		fmt.Printf("Min for clr %d dim %d NaN\n", centroid.BestFitClusterIdx, dim)
-	}
+	return activityPoints
+}
+
+func sortedActivities(activityPoints map[string][]*cluster.FuzzyPoint) []string {
+	activities := []string{}
 
-	if maxCnt == 0 {
-		fmt.Printf("Max for clr %d dim %d NaN\n", centroid.BestFitClusterIdx, dim)
+	for activity := range activityPoints {
+		activities = append(activities, activity)
 	}
 
-	min := cumMin / float64(minCnt)
-	max := cumMax / float64(maxCnt)
+	sort.Strings(activities)
 
-	return min, max
+	return activities
 }
 
 func clusterCenter(leftBound float64, rightBound float64) (float64, error) {
 	if rightBound <= leftBound {
-		return 0.0, fmt.Errorf("Left bound greater than or equal to right bound")
+		return 0.0, fmt.Errorf("%w: %f and %f", ErrInvertedBounds, leftBound, rightBound)
 	}
 
 	return (rightBound + leftBound) / 2, nil
@@ -112,7 +279,7 @@ func clusterCenter(leftBound float64, rightBound float64) (float64, error) {
 
 func clusterWidth(leftBound float64, rightBound float64) (float64, error) {
 	if rightBound <= leftBound {
-		return 0.0, fmt.Errorf("Left bound greater than or equal to right bound")
+		return 0.0, fmt.Errorf("%w: %f and %f", ErrInvertedBounds, leftBound, rightBound)
 	}
 
 	return rightBound - leftBound, nil
@@ -0,0 +1,51 @@
+package number
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+func TestClusterCountSearchFallsBackForActivitiesWithFewPoints(t *testing.T) {
+	points := []*cluster.FuzzyPoint{}
+
+	for _, coord := range []float64{0, 1, 2, 10, 11, 12} {
+		points = append(points, cluster.NewFuzzyPoint([]float64{coord}, "walking"))
+	}
+
+	points = append(points, cluster.NewFuzzyPoint([]float64{5}, "sitting"))
+
+	opts := DefaultRuleSetOptions()
+	opts.Mode = PerActivityClustering
+	opts.ValidityIndex = cluster.DaviesBouldinIndex
+	opts.MaxClusterCount = 3
+	opts.Seed = 1
+	// The single sitting point can't be fitted on its own.
+	opts.BoundsFallback = SkipDimensionFallback
+
+	ruleSet, diagnostics, err := GFNRuleSet(context.Background(), points, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(ruleSet["sitting"]) != 1 {
+		t.Errorf("got %d sitting rules, want 1", len(ruleSet["sitting"]))
+	}
+
+	for _, selection := range diagnostics.ClusterCountSelections {
+		wantTooFewPoints := selection.Activity == "sitting"
+
+		if selection.TooFewPoints != wantTooFewPoints {
+			t.Errorf("got too few points %t for activity %s", selection.TooFewPoints, selection.Activity)
+		}
+
+		if wantTooFewPoints && selection.ClusterCount != 1 {
+			t.Errorf("got cluster count %d for activity %s, want 1", selection.ClusterCount, selection.Activity)
+		}
+	}
+
+	if len(diagnostics.ClusterCountSelections) != 2 {
+		t.Errorf("got %d cluster count selections, want 2", len(diagnostics.ClusterCountSelections))
+	}
+}
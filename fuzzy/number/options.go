@@ -0,0 +1,168 @@
+package number
+
+import (
+	"fmt"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+const (
+	// GlobalClustering clusters all points together and labels each cluster by majority vote.
+	GlobalClustering = "global"
+	// PerActivityClustering clusters the points of each activity separately so every activity gets rules.
+	PerActivityClustering = "per-activity"
+	KMeansClustering      = "kmeans"
+	FuzzyCMeansClustering = "fcm"
+	// GustafsonKesselClustering adapts a covariance matrix per cluster so clusters may be ellipsoids.
+	GustafsonKesselClustering = "gk"
+	// Validity indices need at least two clusters to compare.
+	MinSearchedClusterCount = 2
+	MaxSearchedClusterCount = 6
+)
+
+// RuleSetOptions configures how a fuzzy rule set is generated from points.
+type RuleSetOptions struct {
+	Mode         string
+	Algorithm    string
+	ClusterCount int
+	// ActivityClusterCounts overrides ClusterCount for single activities in per-activity mode.
+	ActivityClusterCounts map[string]int
+	RestartCount          int
+	// ValidityIndex turns on the search for the best cluster count in [MinClusterCount, MaxClusterCount].
+	// Fixed counts from ActivityClusterCounts still take precedence.
+	ValidityIndex   string
+	MinClusterCount int
+	MaxClusterCount int
+	// Seed makes clustering reproducible. Equal seeds yield equal rule sets.
+	Seed int64
+	// DistanceMetric names the metric used for clustering, membership degrees and validity indices.
+	DistanceMetric string
+	// Parallelism bounds the k-means restarts running at once. Values below 1 mean GOMAXPROCS.
+	Parallelism int
+	// EmptyClusterStrategy tells k-means how to recover a cluster which lost all its points.
+	EmptyClusterStrategy string
+	// Fuzzifier only applies to fuzzy c-means and Gustafson-Kessel.
+	Fuzzifier float64
+	// Tolerance and MaxIterCount bound the iterations of every clustering restart.
+	Tolerance    float64
+	MaxIterCount int
+	// Progress may be nil. It observes every clustering restart.
+	Progress cluster.ProgressFunc
+	// BoundsFitting names the strategy fitting the extent of each cluster on each dimension.
+	// Empty picks the DefaultBoundsFitting of Algorithm.
+	BoundsFitting string
+	// BoundsFallback names the policy replacing fuzzy numbers which can't be fitted.
+	BoundsFallback string
+}
+
+func DefaultRuleSetOptions() *RuleSetOptions {
+	return &RuleSetOptions{
+		Mode:                  GlobalClustering,
+		Algorithm:             KMeansClustering,
+		ClusterCount:          OptimalClusterCount,
+		ActivityClusterCounts: map[string]int{},
+		RestartCount:          ClusteringRestartCount,
+		MinClusterCount:       MinSearchedClusterCount,
+		MaxClusterCount:       MaxSearchedClusterCount,
+		DistanceMetric:        cluster.EuclideanDistance,
+		Parallelism:           cluster.DefaultParallelism,
+		EmptyClusterStrategy:  cluster.DefaultEmptyClusterStrategy,
+		Fuzzifier:             cluster.DefaultFuzzifier,
+		Tolerance:             cluster.DefaultConvergenceTolerance,
+		MaxIterCount:          cluster.DefaultMaxIterCount,
+		BoundsFallback:        FailFallback,
+	}
+}
+
+func RuleGenerationModes() []string {
+	return []string{GlobalClustering, PerActivityClustering}
+}
+
+func ClusteringAlgorithms() []string {
+	return []string{KMeansClustering, FuzzyCMeansClustering, GustafsonKesselClustering}
+}
+
+func (o *RuleSetOptions) searchesClusterCount(activity string) bool {
+	if _, ok := o.ActivityClusterCounts[activity]; ok {
+		return false
+	}
+
+	return o.ValidityIndex != ""
+}
+
+func (o *RuleSetOptions) activityClusterCount(activity string) int {
+	if clusterCount, ok := o.ActivityClusterCounts[activity]; ok {
+		return clusterCount
+	}
+
+	return o.ClusterCount
+}
+
+// EffectiveBoundsFitting returns BoundsFitting or the default of Algorithm when it is empty.
+func (o *RuleSetOptions) EffectiveBoundsFitting() string {
+	if o.BoundsFitting == "" {
+		return DefaultBoundsFitting(o.Algorithm)
+	}
+
+	return o.BoundsFitting
+}
+
+func (o *RuleSetOptions) adjustOptions() *cluster.AdjustOptions {
+	return &cluster.AdjustOptions{
+		RestartCount: uint(o.RestartCount),
+		MaxIterCount: o.MaxIterCount,
+		Tolerance:    o.Tolerance,
+		Progress:     o.Progress,
+	}
+}
+
+func (o *RuleSetOptions) validate() error {
+	if o.Mode != GlobalClustering && o.Mode != PerActivityClustering {
+		return fmt.Errorf("Invalid rule generation mode %s", o.Mode)
+	}
+
+	if o.Algorithm != KMeansClustering && o.Algorithm != FuzzyCMeansClustering && o.Algorithm != GustafsonKesselClustering {
+		return fmt.Errorf("Invalid clustering algorithm %s", o.Algorithm)
+	}
+
+	if o.ClusterCount < 1 {
+		return fmt.Errorf("Cluster count must be positive, got %d", o.ClusterCount)
+	}
+
+	for activity, clusterCount := range o.ActivityClusterCounts {
+		if clusterCount < 1 {
+			return fmt.Errorf("Cluster count of activity %s must be positive, got %d", activity, clusterCount)
+		}
+	}
+
+	if o.RestartCount < 1 {
+		return fmt.Errorf("Restart count must be positive, got %d", o.RestartCount)
+	}
+
+	if err := validateBoundsFitting(o.EffectiveBoundsFitting(), o.Algorithm); err != nil {
+		return err
+	}
+
+	if err := validateBoundsFallback(o.BoundsFallback); err != nil {
+		return err
+	}
+
+	if o.ValidityIndex == "" {
+		return nil
+	}
+
+	// Only the name is checked here. The metric is fitted to the points of each super cluster.
+	if _, err := cluster.NewValidityIndex(o.ValidityIndex, nil); err != nil {
+		return err
+	}
+
+	if o.MinClusterCount < MinSearchedClusterCount {
+		return fmt.Errorf("Min cluster count must be at least %d, got %d", MinSearchedClusterCount, o.MinClusterCount)
+	}
+
+	if o.MaxClusterCount < o.MinClusterCount {
+		return fmt.Errorf("Max cluster count %d is less than min cluster count %d", o.MaxClusterCount, o.MinClusterCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package number
+
+import "testing"
+
+func TestEffectiveBoundsFittingDependsOnAlgorithm(t *testing.T) {
+	tests := []struct {
+		algorithm     string
+		boundsFitting string
+		want          string
+	}{
+		{KMeansClustering, "", AveragedFitting},
+		{FuzzyCMeansClustering, "", AveragedFitting},
+		{GustafsonKesselClustering, "", EllipsoidFitting},
+		{GustafsonKesselClustering, KDEFitting, KDEFitting},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultRuleSetOptions()
+		opts.Algorithm = tt.algorithm
+		opts.BoundsFitting = tt.boundsFitting
+
+		if err := opts.validate(); err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.algorithm, err)
+		}
+
+		if got := opts.EffectiveBoundsFitting(); got != tt.want {
+			t.Errorf("got bounds fitting %s for %s, want %s", got, tt.algorithm, tt.want)
+		}
+	}
+}
+
+func TestEllipsoidFittingNeedsGustafsonKessel(t *testing.T) {
+	opts := DefaultRuleSetOptions()
+	opts.BoundsFitting = EllipsoidFitting
+
+	if err := opts.validate(); err == nil {
+		t.Error("expected an error for ellipsoid fitting of k-means clusters")
+	}
+}
@@ -0,0 +1,182 @@
+package number
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	RuleSetFormatVersion = 2
+	RuleSetFilePerm      = 0600
+	// Version 1 stored a single rule per activity.
+	singleRuleFormatVersion = 1
+)
+
+type fuzzyNumDecoder func(params map[string]float64) (FuzzyNum, error)
+
+// Every fuzzy number type must register a decoder here to be loadable.
+var fuzzyNumDecoders = map[string]fuzzyNumDecoder{
+	GaussianFuzzyNum:              gfnFromParams,
+	TriangularFuzzyNum:            tfnFromParams,
+	TrapezoidalFuzzyNum:           trfnFromParams,
+	TwoSidedGaussianFuzzyNum:      tsgfnFromParams,
+	GeneralizedBellFuzzyNum:       gbfnFromParams,
+	SigmoidFuzzyNum:               sfnFromParams,
+	DiffSigmoidFuzzyNum:           dsfnFromParams,
+	PiFuzzyNum:                    pfnFromParams,
+	SShapedFuzzyNum:               ssfnFromParams,
+	ZShapedFuzzyNum:               zsfnFromParams,
+	IntervalType2GaussianFuzzyNum: it2gfnFromParams,
+	UniversalFuzzyNum:             ufnFromParams,
+}
+
+type ruleSetDoc struct {
+	Version int                        `json:"version"`
+	Rules   map[string][][]fuzzyNumDoc `json:"rules"`
+}
+
+type singleRuleSetDoc struct {
+	Version int                      `json:"version"`
+	Rules   map[string][]fuzzyNumDoc `json:"rules"`
+}
+
+type versionDoc struct {
+	Version int `json:"version"`
+}
+
+type fuzzyNumDoc struct {
+	Type   string             `json:"type"`
+	Params map[string]float64 `json:"params"`
+}
+
+func Save(path string, ruleSet FuzzyRuleSet) error {
+	data, err := json.MarshalIndent(ruleSet, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding rule set: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, RuleSetFilePerm); err != nil {
+		return fmt.Errorf("Error writing rule set to %s: %s", path, err)
+	}
+
+	return nil
+}
+
+func Load(path string) (FuzzyRuleSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading rule set from %s: %s", path, err)
+	}
+
+	ruleSet := FuzzyRuleSet{}
+
+	if err := json.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("Error decoding rule set from %s: %s", path, err)
+	}
+
+	return ruleSet, nil
+}
+
+func (f FuzzyRuleSet) MarshalJSON() ([]byte, error) {
+	doc := ruleSetDoc{
+		Version: RuleSetFormatVersion,
+		Rules:   make(map[string][][]fuzzyNumDoc, len(f)),
+	}
+
+	for activity, rules := range f {
+		ruleDocs := [][]fuzzyNumDoc{}
+
+		for _, rule := range rules {
+			fuzzyNumDocs := []fuzzyNumDoc{}
+
+			for _, fuzzyNum := range rule {
+				fuzzyNumDocs = append(fuzzyNumDocs, fuzzyNumDoc{Type: fuzzyNum.Type(), Params: fuzzyNum.Params()})
+			}
+
+			ruleDocs = append(ruleDocs, fuzzyNumDocs)
+		}
+
+		doc.Rules[activity] = ruleDocs
+	}
+
+	return json.Marshal(doc)
+}
+
+func (f *FuzzyRuleSet) UnmarshalJSON(data []byte) error {
+	version := versionDoc{}
+
+	if err := json.Unmarshal(data, &version); err != nil {
+		return err
+	}
+
+	doc := ruleSetDoc{}
+
+	switch version.Version {
+	case RuleSetFormatVersion:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	case singleRuleFormatVersion:
+		singleRuleDoc := singleRuleSetDoc{}
+
+		if err := json.Unmarshal(data, &singleRuleDoc); err != nil {
+			return err
+		}
+
+		doc.Rules = make(map[string][][]fuzzyNumDoc, len(singleRuleDoc.Rules))
+
+		for activity, fuzzyNumDocs := range singleRuleDoc.Rules {
+			doc.Rules[activity] = [][]fuzzyNumDoc{fuzzyNumDocs}
+		}
+	default:
+		return fmt.Errorf("Unsupported rule set format version %d", version.Version)
+	}
+
+	ruleSet := make(FuzzyRuleSet, len(doc.Rules))
+
+	for activity, ruleDocs := range doc.Rules {
+		for ruleIdx, fuzzyNumDocs := range ruleDocs {
+			rule := FuzzyRule{}
+
+			for dim, numDoc := range fuzzyNumDocs {
+				fuzzyNum, err := decodeFuzzyNum(numDoc)
+				if err != nil {
+					return fmt.Errorf("Error decoding fuzzy number on dim %d of rule %d of activity %s: %s", dim, ruleIdx, activity, err)
+				}
+
+				rule = append(rule, fuzzyNum)
+			}
+
+			ruleSet[activity] = append(ruleSet[activity], rule)
+		}
+	}
+
+	*f = ruleSet
+
+	return nil
+}
+
+func decodeFuzzyNum(doc fuzzyNumDoc) (FuzzyNum, error) {
+	decoder, ok := fuzzyNumDecoders[doc.Type]
+	if !ok {
+		return nil, fmt.Errorf("Unknown fuzzy num type %s", doc.Type)
+	}
+
+	return decoder(doc.Params)
+}
+
+func requiredParams(params map[string]float64, names ...string) ([]float64, error) {
+	values := []float64{}
+
+	for _, name := range names {
+		value, ok := params[name]
+		if !ok {
+			return nil, fmt.Errorf("Missing fuzzy num param %s", name)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
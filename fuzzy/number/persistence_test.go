@@ -0,0 +1,119 @@
+package number
+
+import (
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		fuzzyNum func() (FuzzyNum, error)
+	}{
+		{GaussianFuzzyNum, func() (FuzzyNum, error) { return newGaussianFuzzyNum(1, 0.5), nil }},
+		{TriangularFuzzyNum, func() (FuzzyNum, error) { return newTriangularFuzzyNum(-1, 0.25, 2), nil }},
+		{TrapezoidalFuzzyNum, func() (FuzzyNum, error) { return newTrapezoidalFuzzyNum(-1, 0, 1, 3), nil }},
+		{TwoSidedGaussianFuzzyNum, func() (FuzzyNum, error) { return newTwoSidedGaussianFuzzyNum(1, 0.5, 2), nil }},
+		{GeneralizedBellFuzzyNum, func() (FuzzyNum, error) { return newGeneralizedBellFuzzyNum(1, 2, 3) }},
+		{SigmoidFuzzyNum, func() (FuzzyNum, error) { return newSigmoidFuzzyNum(-2, 1) }},
+		{DiffSigmoidFuzzyNum, func() (FuzzyNum, error) { return newDiffSigmoidFuzzyNum(4, -1, 4, 1) }},
+		{PiFuzzyNum, func() (FuzzyNum, error) { return newPiFuzzyNum(-2, -1, 1, 2) }},
+		{SShapedFuzzyNum, func() (FuzzyNum, error) { return newSShapedFuzzyNum(-1, 1) }},
+		{ZShapedFuzzyNum, func() (FuzzyNum, error) { return newZShapedFuzzyNum(-1, 1) }},
+		{IntervalType2GaussianFuzzyNum, func() (FuzzyNum, error) { return newIT2GaussianFuzzyNum(1, 0.5, 1) }},
+		{UniversalFuzzyNum, func() (FuzzyNum, error) { return newUniversalFuzzyNum(), nil }},
+	}
+
+	tested := map[string]bool{}
+
+	for _, test := range tests {
+		tested[test.name] = true
+
+		t.Run(test.name, func(t *testing.T) {
+			fuzzyNum, err := test.fuzzyNum()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if fuzzyNum.Type() != test.name {
+				t.Fatalf("got fuzzy number type %s, want %s", fuzzyNum.Type(), test.name)
+			}
+
+			path := filepath.Join(t.TempDir(), "rules.json")
+
+			if err := Save(path, FuzzyRuleSet{"walking": {{fuzzyNum}}}); err != nil {
+				t.Fatalf("unexpected save error: %s", err)
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("unexpected load error: %s", err)
+			}
+
+			assertSameFuzzyNum(t, fuzzyNum, loaded["walking"][0][0])
+		})
+	}
+
+	for fuzzyNumType := range fuzzyNumDecoders {
+		if !tested[fuzzyNumType] {
+			t.Errorf("fuzzy number type %s has no round trip test", fuzzyNumType)
+		}
+	}
+}
+
+func TestLoadSingleRuleFormat(t *testing.T) {
+	data := `{
+  "version": 1,
+  "rules": {
+    "sitting": [
+      {"type": "gaussian", "params": {"mean": 1, "stdDev": 0.5}},
+      {"type": "triangular", "params": {"left": -1, "center": 0, "right": 1}}
+    ]
+  }
+}`
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+
+	if err := ioutil.WriteFile(path, []byte(data), RuleSetFilePerm); err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected load error: %s", err)
+	}
+
+	if len(loaded) != 1 || len(loaded["sitting"]) != 1 {
+		t.Fatalf("got rule set %v, want a single sitting rule", loaded)
+	}
+
+	rule := loaded["sitting"][0]
+
+	if len(rule) != 2 {
+		t.Fatalf("got %d fuzzy numbers, want 2", len(rule))
+	}
+
+	assertSameFuzzyNum(t, newGaussianFuzzyNum(1, 0.5), rule[0])
+	assertSameFuzzyNum(t, newTriangularFuzzyNum(-1, 0, 1), rule[1])
+}
+
+func assertSameFuzzyNum(t *testing.T, want, got FuzzyNum) {
+	t.Helper()
+
+	if got.Type() != want.Type() {
+		t.Fatalf("got fuzzy number type %s, want %s", got.Type(), want.Type())
+	}
+
+	if !reflect.DeepEqual(got.Params(), want.Params()) {
+		t.Fatalf("got params %v, want %v", got.Params(), want.Params())
+	}
+
+	for _, x := range []float64{-3, -1, -0.5, 0, 0.5, 1, 2, 3} {
+		if math.Abs(got.MembershipDegree(x)-want.MembershipDegree(x)) > fittingTolerance {
+			t.Fatalf("got membership degree %f at %f, want %f", got.MembershipDegree(x), x, want.MembershipDegree(x))
+		}
+	}
+}
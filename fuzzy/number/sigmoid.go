@@ -0,0 +1,180 @@
+package number
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+// SigmoidCentroidDegree is the membership degree a sigmoid built from a cluster reaches at the centroid.
+const SigmoidCentroidDegree = 0.95
+
+// sigmoidFuzzyNum is open-ended. It rises to the right for a positive slope and to the left otherwise.
+type sigmoidFuzzyNum struct {
+	slope     float64
+	crossover float64
+}
+
+func SFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, sfnFromCluster)
+}
+
+func (s *sigmoidFuzzyNum) MembershipDegree(x float64) float64 {
+	return sigmoid(x, s.slope, s.crossover)
+}
+
+func (s *sigmoidFuzzyNum) String() string {
+	return fmt.Sprintf("slope: %f, crossover: %f", s.slope, s.crossover)
+}
+
+func (s *sigmoidFuzzyNum) Type() string {
+	return SigmoidFuzzyNum
+}
+
+func (s *sigmoidFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"slope": s.slope, "crossover": s.crossover}
+}
+
+func (s *sigmoidFuzzyNum) validate() error {
+	if s.slope == 0 || math.IsInf(s.slope, 0) || math.IsNaN(s.slope) {
+		return fmt.Errorf("SFN slope must be finite and non-zero, got %f", s.slope)
+	}
+
+	return nil
+}
+
+// sfnFromCluster opens the sigmoid towards the side of the other clusters the cluster lies on.
+func sfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting SFN bounds: %w", err)
+	}
+	centroidCoord := centroid.Coords[dim]
+
+	if _, err := clusterWidth(leftBound, rightBound); err != nil {
+		return nil, fmt.Errorf("Error getting SFN bounds: %w", err)
+	}
+
+	if isUpperCluster(points, centroid, dim) {
+		return newSigmoidFuzzyNum(sigmoidSlope(leftBound, centroidCoord), leftBound)
+	}
+
+	return newSigmoidFuzzyNum(-sigmoidSlope(centroidCoord, rightBound), rightBound)
+}
+
+func newSigmoidFuzzyNum(slope, crossover float64) (FuzzyNum, error) {
+	s := &sigmoidFuzzyNum{
+		slope:     slope,
+		crossover: crossover,
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func sfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "slope", "crossover")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding SFN: %s", err)
+	}
+
+	return newSigmoidFuzzyNum(values[0], values[1])
+}
+
+// diffSigmoidFuzzyNum is the difference of a left and a right sigmoid which gives a closed bump with soft flanks.
+type diffSigmoidFuzzyNum struct {
+	leftSlope      float64
+	leftCrossover  float64
+	rightSlope     float64
+	rightCrossover float64
+}
+
+func DSFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, dsfnFromCluster)
+}
+
+func (d *diffSigmoidFuzzyNum) MembershipDegree(x float64) float64 {
+	degree := sigmoid(x, d.leftSlope, d.leftCrossover) - sigmoid(x, d.rightSlope, d.rightCrossover)
+	return math.Max(0, math.Min(1, degree))
+}
+
+func (d *diffSigmoidFuzzyNum) String() string {
+	return fmt.Sprintf("left slope: %f, left crossover: %f, right slope: %f, right crossover: %f", d.leftSlope, d.leftCrossover, d.rightSlope, d.rightCrossover)
+}
+
+func (d *diffSigmoidFuzzyNum) Type() string {
+	return DiffSigmoidFuzzyNum
+}
+
+func (d *diffSigmoidFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{
+		"leftSlope":      d.leftSlope,
+		"leftCrossover":  d.leftCrossover,
+		"rightSlope":     d.rightSlope,
+		"rightCrossover": d.rightCrossover,
+	}
+}
+
+func (d *diffSigmoidFuzzyNum) validate() error {
+	if d.leftSlope <= 0 || d.rightSlope <= 0 || math.IsInf(d.leftSlope, 0) || math.IsInf(d.rightSlope, 0) {
+		return fmt.Errorf("DSFN slopes must be finite and positive, got %f and %f", d.leftSlope, d.rightSlope)
+	}
+
+	if d.rightCrossover <= d.leftCrossover {
+		return fmt.Errorf("DSFN left crossover %f must be less than right crossover %f", d.leftCrossover, d.rightCrossover)
+	}
+
+	return nil
+}
+
+func dsfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting DSFN bounds: %w", err)
+	}
+	centroidCoord := centroid.Coords[dim]
+
+	if _, err := clusterWidth(leftBound, rightBound); err != nil {
+		return nil, fmt.Errorf("Error getting DSFN bounds: %w", err)
+	}
+
+	return newDiffSigmoidFuzzyNum(sigmoidSlope(leftBound, centroidCoord), leftBound, sigmoidSlope(centroidCoord, rightBound), rightBound)
+}
+
+func newDiffSigmoidFuzzyNum(leftSlope, leftCrossover, rightSlope, rightCrossover float64) (FuzzyNum, error) {
+	d := &diffSigmoidFuzzyNum{
+		leftSlope:      leftSlope,
+		leftCrossover:  leftCrossover,
+		rightSlope:     rightSlope,
+		rightCrossover: rightCrossover,
+	}
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func dsfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "leftSlope", "leftCrossover", "rightSlope", "rightCrossover")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding DSFN: %s", err)
+	}
+
+	return newDiffSigmoidFuzzyNum(values[0], values[1], values[2], values[3])
+}
+
+func sigmoid(x, slope, crossover float64) float64 {
+	return 1 / (1 + math.Exp(-slope*(x-crossover)))
+}
+
+// sigmoidSlope returns the slope of a sigmoid crossing 0.5 at from and reaching SigmoidCentroidDegree at to.
+func sigmoidSlope(from, to float64) float64 {
+	return math.Log(SigmoidCentroidDegree/(1-SigmoidCentroidDegree)) / math.Abs(to-from)
+}
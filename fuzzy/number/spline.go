@@ -0,0 +1,263 @@
+package number
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+// sShapedFuzzyNum rises smoothly from 0 at foot to 1 at shoulder and stays 1 beyond.
+type sShapedFuzzyNum struct {
+	foot     float64
+	shoulder float64
+}
+
+func SSFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, ssfnFromCluster)
+}
+
+func (s *sShapedFuzzyNum) MembershipDegree(x float64) float64 {
+	return sSpline(x, s.foot, s.shoulder)
+}
+
+func (s *sShapedFuzzyNum) String() string {
+	return fmt.Sprintf("foot: %f, shoulder: %f", s.foot, s.shoulder)
+}
+
+func (s *sShapedFuzzyNum) Type() string {
+	return SShapedFuzzyNum
+}
+
+func (s *sShapedFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"foot": s.foot, "shoulder": s.shoulder}
+}
+
+func ssfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, _, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting SSFN bounds: %w", err)
+	}
+
+	return newSShapedFuzzyNum(leftBound, centroid.Coords[dim])
+}
+
+func newSShapedFuzzyNum(foot, shoulder float64) (FuzzyNum, error) {
+	if !(foot < shoulder) {
+		return nil, fmt.Errorf("SSFN foot %f must be less than shoulder %f", foot, shoulder)
+	}
+
+	return &sShapedFuzzyNum{foot: foot, shoulder: shoulder}, nil
+}
+
+func ssfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "foot", "shoulder")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding SSFN: %s", err)
+	}
+
+	return newSShapedFuzzyNum(values[0], values[1])
+}
+
+// zShapedFuzzyNum is 1 up to shoulder and falls smoothly to 0 at foot.
+type zShapedFuzzyNum struct {
+	shoulder float64
+	foot     float64
+}
+
+func ZSFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, zsfnFromCluster)
+}
+
+func (z *zShapedFuzzyNum) MembershipDegree(x float64) float64 {
+	return 1 - sSpline(x, z.shoulder, z.foot)
+}
+
+func (z *zShapedFuzzyNum) String() string {
+	return fmt.Sprintf("shoulder: %f, foot: %f", z.shoulder, z.foot)
+}
+
+func (z *zShapedFuzzyNum) Type() string {
+	return ZShapedFuzzyNum
+}
+
+func (z *zShapedFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"shoulder": z.shoulder, "foot": z.foot}
+}
+
+func zsfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	_, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting ZSFN bounds: %w", err)
+	}
+
+	return newZShapedFuzzyNum(centroid.Coords[dim], rightBound)
+}
+
+func newZShapedFuzzyNum(shoulder, foot float64) (FuzzyNum, error) {
+	if !(shoulder < foot) {
+		return nil, fmt.Errorf("ZSFN shoulder %f must be less than foot %f", shoulder, foot)
+	}
+
+	return &zShapedFuzzyNum{shoulder: shoulder, foot: foot}, nil
+}
+
+func zsfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "shoulder", "foot")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding ZSFN: %s", err)
+	}
+
+	return newZShapedFuzzyNum(values[0], values[1])
+}
+
+// piFuzzyNum rises like an S-shape, keeps a plateau and falls like a Z-shape.
+type piFuzzyNum struct {
+	left       float64
+	innerLeft  float64
+	innerRight float64
+	right      float64
+}
+
+func PFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, pfnFromCluster)
+}
+
+func (p *piFuzzyNum) MembershipDegree(x float64) float64 {
+	if x < p.innerLeft {
+		return sSpline(x, p.left, p.innerLeft)
+	}
+
+	if x > p.innerRight {
+		return 1 - sSpline(x, p.innerRight, p.right)
+	}
+
+	return 1.0
+}
+
+func (p *piFuzzyNum) String() string {
+	return fmt.Sprintf("left: %f, inner left: %f, inner right: %f, right: %f", p.left, p.innerLeft, p.innerRight, p.right)
+}
+
+func (p *piFuzzyNum) Type() string {
+	return PiFuzzyNum
+}
+
+func (p *piFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"left": p.left, "innerLeft": p.innerLeft, "innerRight": p.innerRight, "right": p.right}
+}
+
+func pfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	left, innerLeft, innerRight, right, err := clusterPlateau(fitter, points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting PFN bounds: %w", err)
+	}
+
+	return newPiFuzzyNum(left, innerLeft, innerRight, right)
+}
+
+func newPiFuzzyNum(left, innerLeft, innerRight, right float64) (FuzzyNum, error) {
+	if !(left <= innerLeft && innerLeft <= innerRight && innerRight <= right && left < right) {
+		return nil, fmt.Errorf("PFN bounds must satisfy left <= inner left <= inner right <= right, got %f, %f, %f, %f", left, innerLeft, innerRight, right)
+	}
+
+	return &piFuzzyNum{left: left, innerLeft: innerLeft, innerRight: innerRight, right: right}, nil
+}
+
+func pfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "left", "innerLeft", "innerRight", "right")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding PFN: %s", err)
+	}
+
+	return newPiFuzzyNum(values[0], values[1], values[2], values[3])
+}
+
+func ShoulderRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, shoulderFromCluster)
+}
+
+// shoulderFromCluster opens the outermost clusters of an axis with Z- and S-shapes and closes the inner ones with pi-shapes.
+func shoulderFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	left, innerLeft, innerRight, right, err := clusterPlateau(fitter, points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting shoulder bounds: %w", err)
+	}
+
+	lowest, highest := clusterRank(points, centroid, dim)
+
+	switch {
+	case lowest && !highest && innerRight < right:
+		return newZShapedFuzzyNum(innerRight, right)
+	case highest && !lowest && left < innerLeft:
+		return newSShapedFuzzyNum(left, innerLeft)
+	default:
+		return newPiFuzzyNum(left, innerLeft, innerRight, right)
+	}
+}
+
+// sSpline rises from 0 at foot to 1 at shoulder with two quadratic pieces meeting at their midpoint.
+func sSpline(x, foot, shoulder float64) float64 {
+	mid := (foot + shoulder) / 2
+
+	switch {
+	case x <= foot:
+		return 0.0
+	case x <= mid:
+		ratio := (x - foot) / (shoulder - foot)
+		return 2 * ratio * ratio
+	case x < shoulder:
+		ratio := (x - shoulder) / (shoulder - foot)
+		return 1 - 2*ratio*ratio
+	default:
+		return 1.0
+	}
+}
+
+// clusterRank tells whether the cluster of centroid has the lowest or the highest mean coordinate on dim.
+func clusterRank(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (bool, bool) {
+	cumCoords := make(map[int]float64)
+	counts := make(map[int]int)
+
+	for _, point := range points {
+		cumCoords[point.BestFitClusterIdx] += point.Coords[dim]
+		counts[point.BestFitClusterIdx]++
+	}
+
+	ownMean := centroid.Coords[dim]
+
+	if count, ok := counts[centroid.BestFitClusterIdx]; ok {
+		ownMean = cumCoords[centroid.BestFitClusterIdx] / float64(count)
+	}
+
+	lowest, highest := true, true
+
+	for clusterIdx, count := range counts {
+		if clusterIdx == centroid.BestFitClusterIdx {
+			continue
+		}
+
+		mean := cumCoords[clusterIdx] / float64(count)
+
+		if mean < ownMean {
+			lowest = false
+		}
+
+		if mean > ownMean {
+			highest = false
+		}
+	}
+
+	return lowest, highest
+}
+
+// isUpperCluster tells whether the cluster lies above the mean of all cluster points on dim.
+func isUpperCluster(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) bool {
+	cumCoord := 0.0
+
+	for _, point := range points {
+		cumCoord += point.Coords[dim]
+	}
+
+	return centroid.Coords[dim] >= cumCoord/float64(len(points))
+}
@@ -0,0 +1,111 @@
+package number
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+// TrFNSupportSpread is how many times farther from the centroid than the fitted cluster bounds the support of a TrFN reaches.
+// The fitted bounds only span the typical points on each axis, so a reading rarely lies within all of them at once and no rule would fire.
+const TrFNSupportSpread = 4.0
+
+type trapezoidalFuzzyNum struct {
+	left       float64
+	innerLeft  float64
+	innerRight float64
+	right      float64
+}
+
+func TrFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, trfnFromCluster)
+}
+
+func (t *trapezoidalFuzzyNum) MembershipDegree(x float64) float64 {
+	switch {
+	case x < t.left:
+		return 0.0
+	case x < t.innerLeft:
+		return (x - t.left) / (t.innerLeft - t.left)
+	case x <= t.innerRight:
+		return 1.0
+	case x < t.right:
+		return (t.right - x) / (t.right - t.innerRight)
+	default:
+		return 0.0
+	}
+}
+
+func (t *trapezoidalFuzzyNum) String() string {
+	return fmt.Sprintf("left: %2.f, inner left: %2.f, inner right: %2.f, right: %2.f", t.left, t.innerLeft, t.innerRight, t.right)
+}
+
+func (t *trapezoidalFuzzyNum) Type() string {
+	return TrapezoidalFuzzyNum
+}
+
+func (t *trapezoidalFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"left": t.left, "innerLeft": t.innerLeft, "innerRight": t.innerRight, "right": t.right}
+}
+
+// trfnFromCluster spreads the support beyond the cluster bounds and spans the plateau over the bounds of its most typical points.
+func trfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	left, innerLeft, innerRight, right, err := clusterPlateau(fitter, points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting TrFN bounds: %w", err)
+	}
+
+	centroidCoord := centroid.Coords[dim]
+	left = centroidCoord - TrFNSupportSpread*(centroidCoord-left)
+	right = centroidCoord + TrFNSupportSpread*(right-centroidCoord)
+
+	return newTrapezoidalFuzzyNum(left, innerLeft, innerRight, right), nil
+}
+
+// clusterPlateau returns the support of a cluster together with its core clamped into the support.
+func clusterPlateau(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (float64, float64, float64, float64, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	if _, err := clusterWidth(leftBound, rightBound); err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	innerLeftBound, innerRightBound, err := fitter.core(points, centroid, dim)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	innerLeftBound = math.Max(leftBound, math.Min(innerLeftBound, rightBound))
+	innerRightBound = math.Max(innerLeftBound, math.Min(innerRightBound, rightBound))
+
+	return leftBound, innerLeftBound, innerRightBound, rightBound, nil
+}
+
+func newTrapezoidalFuzzyNum(left, innerLeft, innerRight, right float64) FuzzyNum {
+	return &trapezoidalFuzzyNum{
+		left:       left,
+		innerLeft:  innerLeft,
+		innerRight: innerRight,
+		right:      right,
+	}
+}
+
+func trfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "left", "innerLeft", "innerRight", "right")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding TrFN: %s", err)
+	}
+
+	left, innerLeft, innerRight, right := values[0], values[1], values[2], values[3]
+
+	if !(left <= innerLeft && innerLeft <= innerRight && innerRight <= right && left < right) {
+		return nil, fmt.Errorf("TrFN bounds must satisfy left <= inner left <= inner right <= right, got %f, %f, %f, %f", left, innerLeft, innerRight, right)
+	}
+
+	return newTrapezoidalFuzzyNum(left, innerLeft, innerRight, right), nil
+}
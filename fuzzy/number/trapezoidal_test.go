@@ -0,0 +1,18 @@
+package number
+
+import "testing"
+
+func TestTrFNSupportSpreadsBeyondClusterBounds(t *testing.T) {
+	points := clusterPoints([]float64{1, 3}, []float64{1, 1})
+
+	fuzzyNum, err := trfnFromCluster(&weightedFitter{}, points, clusterCentroid(2), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The weighted support of the cluster is [1, 3] around the centroid 2 and its core is [1.5, 2.5].
+	params := fuzzyNum.Params()
+
+	assertBounds(t, 2-TrFNSupportSpread, 2+TrFNSupportSpread, params["left"], params["right"])
+	assertBounds(t, 1.5, 2.5, params["innerLeft"], params["innerRight"])
+}
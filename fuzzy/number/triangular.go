@@ -1,6 +1,7 @@
 package number
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/IvanHristov98/postato/cluster"
@@ -12,8 +13,8 @@ type triangularFuzzyNum struct {
 	right  float64
 }
 
-func TFNRuleSet(points []*cluster.FuzzyPoint) (FuzzyRuleSet, error) {
-	return fuzzyNumRuleSet(points, tfnFromCluster)
+func TFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, tfnFromCluster)
 }
 
 func (t *triangularFuzzyNum) MembershipDegree(x float64) float64 {
@@ -33,12 +34,23 @@ func (t *triangularFuzzyNum) String() string {
 	return fmt.Sprintf("left: %2.f, center: %2.f, right: %2.f", t.left, t.center, t.right)
 }
 
-func tfnFromCluster(points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
-	leftBound, rightBound := clusterBounds(points, centroid, dim)
+func (t *triangularFuzzyNum) Type() string {
+	return TriangularFuzzyNum
+}
+
+func (t *triangularFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"left": t.left, "center": t.center, "right": t.right}
+}
+
+func tfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting TFN bounds: %w", err)
+	}
 
 	mean, err := clusterCenter(leftBound, rightBound)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting GFN mean: %s", err)
+		return nil, fmt.Errorf("Error getting GFN mean: %w", err)
 	}
 
 	return newTriangularFuzzyNum(leftBound, mean, rightBound), nil
@@ -51,3 +63,18 @@ func newTriangularFuzzyNum(left, center, right float64) FuzzyNum {
 		right:  right,
 	}
 }
+
+func tfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "left", "center", "right")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding TFN: %s", err)
+	}
+
+	left, center, right := values[0], values[1], values[2]
+
+	if !(left < center && center < right) {
+		return nil, fmt.Errorf("TFN bounds must satisfy left < center < right, got %f, %f, %f", left, center, right)
+	}
+
+	return newTriangularFuzzyNum(left, center, right), nil
+}
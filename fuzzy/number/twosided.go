@@ -0,0 +1,88 @@
+package number
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+// twoSidedGaussianFuzzyNum uses separate standard deviations left and right of its mean to capture skew.
+type twoSidedGaussianFuzzyNum struct {
+	mean        float64
+	leftStdDev  float64
+	rightStdDev float64
+}
+
+func TSGFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, tsgfnFromCluster)
+}
+
+func (t *twoSidedGaussianFuzzyNum) MembershipDegree(x float64) float64 {
+	stdDev := t.rightStdDev
+
+	if x < t.mean {
+		stdDev = t.leftStdDev
+	}
+
+	numer := -math.Pow(x-t.mean, 2)
+	denom := 2 * math.Pow(stdDev, 2)
+	return math.Exp(numer / denom)
+}
+
+func (t *twoSidedGaussianFuzzyNum) String() string {
+	return fmt.Sprintf("mean: %f, left std dev: %f, right std dev: %f", t.mean, t.leftStdDev, t.rightStdDev)
+}
+
+func (t *twoSidedGaussianFuzzyNum) Type() string {
+	return TwoSidedGaussianFuzzyNum
+}
+
+func (t *twoSidedGaussianFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"mean": t.mean, "leftStdDev": t.leftStdDev, "rightStdDev": t.rightStdDev}
+}
+
+func tsgfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting TSGFN bounds: %w", err)
+	}
+	mean := centroid.Coords[dim]
+
+	// Each side doubles its distance to the mean so a centered mean yields the GFN of the same cluster.
+	leftStdDev, err := clusterWidth(leftBound, mean)
+	if err != nil {
+		return nil, fmt.Errorf("Error obtaining TSGFN left standard deviation: %w", err)
+	}
+
+	rightStdDev, err := clusterWidth(mean, rightBound)
+	if err != nil {
+		return nil, fmt.Errorf("Error obtaining TSGFN right standard deviation: %w", err)
+	}
+
+	return newTwoSidedGaussianFuzzyNum(mean, 2*leftStdDev, 2*rightStdDev), nil
+}
+
+func newTwoSidedGaussianFuzzyNum(mean, leftStdDev, rightStdDev float64) FuzzyNum {
+	return &twoSidedGaussianFuzzyNum{
+		mean:        mean,
+		leftStdDev:  leftStdDev,
+		rightStdDev: rightStdDev,
+	}
+}
+
+func tsgfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "mean", "leftStdDev", "rightStdDev")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding TSGFN: %s", err)
+	}
+
+	mean, leftStdDev, rightStdDev := values[0], values[1], values[2]
+
+	if leftStdDev <= 0 || rightStdDev <= 0 {
+		return nil, fmt.Errorf("TSGFN standard deviations must be positive, got %f and %f", leftStdDev, rightStdDev)
+	}
+
+	return newTwoSidedGaussianFuzzyNum(mean, leftStdDev, rightStdDev), nil
+}
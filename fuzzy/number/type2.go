@@ -0,0 +1,130 @@
+package number
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IvanHristov98/postato/cluster"
+)
+
+// FootprintRatio is the least relative spread of the standard deviations of an interval type-2 GFN around the GFN of the same cluster.
+const FootprintRatio = 0.1
+
+// IntervalType2FuzzyNum is a fuzzy number whose membership degree is only known to lie in an interval.
+// The area between its lower and upper membership functions is its footprint of uncertainty.
+type IntervalType2FuzzyNum interface {
+	FuzzyNum
+	// MembershipInterval returns the lower and upper membership degrees of x.
+	MembershipInterval(x float64) (float64, float64)
+}
+
+// MembershipInterval returns the membership degree interval of x in any fuzzy number.
+// Type-1 fuzzy numbers yield a degenerate interval.
+func MembershipInterval(fuzzyNum FuzzyNum, x float64) (float64, float64) {
+	if it2FuzzyNum, ok := fuzzyNum.(IntervalType2FuzzyNum); ok {
+		return it2FuzzyNum.MembershipInterval(x)
+	}
+
+	degree := fuzzyNum.MembershipDegree(x)
+	return degree, degree
+}
+
+// it2GaussianFuzzyNum is a gaussian with a certain mean and an uncertain standard deviation.
+type it2GaussianFuzzyNum struct {
+	mean        float64
+	lowerStdDev float64
+	upperStdDev float64
+}
+
+func IT2GFNRuleSet(ctx context.Context, points []*cluster.FuzzyPoint, opts *RuleSetOptions) (FuzzyRuleSet, *Diagnostics, error) {
+	return fuzzyNumRuleSet(ctx, points, opts, it2gfnFromCluster)
+}
+
+// MembershipDegree is the middle of the membership interval so type-1 consumers such as plots still work.
+func (i *it2GaussianFuzzyNum) MembershipDegree(x float64) float64 {
+	lower, upper := i.MembershipInterval(x)
+	return (lower + upper) / 2
+}
+
+func (i *it2GaussianFuzzyNum) MembershipInterval(x float64) (float64, float64) {
+	return gaussian(x, i.mean, i.lowerStdDev), gaussian(x, i.mean, i.upperStdDev)
+}
+
+func (i *it2GaussianFuzzyNum) String() string {
+	return fmt.Sprintf("mean: %f, lower std dev: %f, upper std dev: %f", i.mean, i.lowerStdDev, i.upperStdDev)
+}
+
+func (i *it2GaussianFuzzyNum) Type() string {
+	return IntervalType2GaussianFuzzyNum
+}
+
+func (i *it2GaussianFuzzyNum) Params() map[string]float64 {
+	return map[string]float64{"mean": i.mean, "lowerStdDev": i.lowerStdDev, "upperStdDev": i.upperStdDev}
+}
+
+func (i *it2GaussianFuzzyNum) validate() error {
+	if i.lowerStdDev <= 0 {
+		return fmt.Errorf("IT2GFN lower standard deviation must be positive, got %f", i.lowerStdDev)
+	}
+
+	if i.upperStdDev < i.lowerStdDev {
+		return fmt.Errorf("IT2GFN upper standard deviation %f must not be less than lower %f", i.upperStdDev, i.lowerStdDev)
+	}
+
+	return nil
+}
+
+// it2gfnFromCluster centers the number at the centroid and takes the uncertainty of its standard deviation
+// from the different spreads on both sides of it.
+// A symmetric cluster still gets a footprint of at least FootprintRatio around its GFN.
+func it2gfnFromCluster(fitter boundsFitter, points []*cluster.FuzzyPoint, centroid *cluster.FuzzyPoint, dim int) (FuzzyNum, error) {
+	leftBound, rightBound, err := fitter.support(points, centroid, dim)
+	if err != nil {
+		return nil, fmt.Errorf("Error fitting IT2GFN bounds: %w", err)
+	}
+	centroidCoord := centroid.Coords[dim]
+
+	stdDev, err := clusterWidth(leftBound, rightBound)
+	if err != nil {
+		return nil, fmt.Errorf("Error obtaining IT2GFN standard deviation: %w", err)
+	}
+
+	// Like in the TSGFN each side doubles its distance so a centered centroid yields stdDev on both sides.
+	leftStdDev := 2 * math.Abs(centroidCoord-leftBound)
+	rightStdDev := 2 * math.Abs(rightBound-centroidCoord)
+
+	lowerStdDev := math.Min(math.Min(leftStdDev, rightStdDev), stdDev*(1-FootprintRatio))
+	upperStdDev := math.Max(math.Max(leftStdDev, rightStdDev), stdDev*(1+FootprintRatio))
+
+	return newIT2GaussianFuzzyNum(centroidCoord, math.Max(lowerStdDev, stdDev*FootprintRatio), upperStdDev)
+}
+
+func newIT2GaussianFuzzyNum(mean, lowerStdDev, upperStdDev float64) (FuzzyNum, error) {
+	i := &it2GaussianFuzzyNum{
+		mean:        mean,
+		lowerStdDev: lowerStdDev,
+		upperStdDev: upperStdDev,
+	}
+
+	if err := i.validate(); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func it2gfnFromParams(params map[string]float64) (FuzzyNum, error) {
+	values, err := requiredParams(params, "mean", "lowerStdDev", "upperStdDev")
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding IT2GFN: %s", err)
+	}
+
+	return newIT2GaussianFuzzyNum(values[0], values[1], values[2])
+}
+
+func gaussian(x, mean, stdDev float64) float64 {
+	numer := -math.Pow(x-mean, 2)
+	denom := 2 * math.Pow(stdDev, 2)
+	return math.Exp(numer / denom)
+}
@@ -3,7 +3,7 @@ module github.com/IvanHristov98/postato
 go 1.15
 
 require (
-	github.com/akamensky/argparse v1.2.2 // indirect
+	github.com/akamensky/argparse v1.2.2
 	github.com/fogleman/gg v1.3.0
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
 	golang.org/x/image v0.0.0-20201208152932-35266b937fa6 // indirect
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/IvanHristov98/postato/fuzzy/number"
+)
+
+const (
+	FormatVersion = 1
+	FilePerm      = 0600
+)
+
+// Metadata describes how a model was trained so it can be traced back to its data.
+type Metadata struct {
+	FuzzyNumType        string `json:"fuzzyNumType"`
+	FeatureCount        int    `json:"featureCount"`
+	RuleGenerationMode  string `json:"ruleGenerationMode"`
+	ClusteringAlgorithm string `json:"clusteringAlgorithm"`
+	// ClusterCount is the cluster count of global clustering or of the activities missing from ActivityClusterCounts.
+	// It is omitted when every activity is listed there.
+	ClusterCount int `json:"clusterCount,omitempty"`
+	// ActivityClusterCounts maps each activity to its cluster count in per-activity clustering.
+	ActivityClusterCounts map[string]int `json:"activityClusterCounts,omitempty"`
+	RestartCount          int            `json:"restartCount"`
+	ValidityIndex         string         `json:"validityIndex,omitempty"`
+	Fuzzifier             float64        `json:"fuzzifier,omitempty"`
+	BoundsFitting         string         `json:"boundsFitting,omitempty"`
+	BoundsFallback        string         `json:"boundsFallback,omitempty"`
+	EmptyClusterStrategy  string         `json:"emptyClusterStrategy,omitempty"`
+	DistanceMetric        string         `json:"distanceMetric,omitempty"`
+	Seed                  int64          `json:"seed"`
+	DatasetChecksum       string         `json:"datasetChecksum"`
+	TrainedAt             time.Time      `json:"trainedAt"`
+	// Diagnostics holds the decisions taken during rule generation such as the selected cluster counts.
+	Diagnostics *number.Diagnostics `json:"diagnostics,omitempty"`
+}
+
+type Model struct {
+	Version  int                 `json:"version"`
+	Metadata Metadata            `json:"metadata"`
+	RuleSet  number.FuzzyRuleSet `json:"ruleSet"`
+}
+
+func NewModel(metadata Metadata, ruleSet number.FuzzyRuleSet) *Model {
+	return &Model{
+		Version:  FormatVersion,
+		Metadata: metadata,
+		RuleSet:  ruleSet,
+	}
+}
+
+func Save(path string, m *Model) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding model: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, FilePerm); err != nil {
+		return fmt.Errorf("Error writing model to %s: %s", path, err)
+	}
+
+	return nil
+}
+
+func Load(path string) (*Model, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading model from %s: %s", path, err)
+	}
+
+	m := &Model{}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("Error decoding model from %s: %s", path, err)
+	}
+
+	if m.Version != FormatVersion {
+		return nil, fmt.Errorf("Unsupported model format version %d", m.Version)
+	}
+
+	if err := m.validateRuleLengths(); err != nil {
+		return nil, fmt.Errorf("Invalid model %s: %s", path, err)
+	}
+
+	return m, nil
+}
+
+// validateRuleLengths makes sure every rule holds a fuzzy number per feature.
+func (m *Model) validateRuleLengths() error {
+	for activity, rules := range m.RuleSet {
+		for ruleIdx, rule := range rules {
+			if len(rule) != m.Metadata.FeatureCount {
+				return fmt.Errorf("Rule %d of activity %s has %d fuzzy numbers but the model has %d features", ruleIdx, activity, len(rule), m.Metadata.FeatureCount)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DatasetChecksum returns the hex encoded SHA-256 digest of the file at path.
+func DatasetChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("Unable to read dataset: %s", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("Error hashing dataset: %s", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -36,13 +36,13 @@ type dataPoint struct {
 	y float64
 }
 
-func DrawFuzzyNums(num fn.FuzzyNum, low, high float64, dim int, activity, imagePath string) error {
+func DrawFuzzyNums(num fn.FuzzyNum, low, high float64, dim int, activity, imagePath string, rnd *rand.Rand) error {
 	dc, err := drawingCanvas(low, high, dim, activity)
 	if err != nil {
 		return fmt.Errorf("Error initializing canvas: %s", err)
 	}
 
-	drawFuzzyNum(dc, num, low, high)
+	drawFuzzyNum(dc, num, low, high, rnd)
 
 	if err := dc.SavePNG(imagePath); err != nil {
 		return fmt.Errorf("Error saving png %s: %s", imagePath, err)
@@ -117,7 +117,7 @@ func fontLocation() string {
 	return fmt.Sprintf("%s%s%s", fontDir, string(os.PathSeparator), Font)
 }
 
-func drawFuzzyNum(dc *gg.Context, num fn.FuzzyNum, low, high float64) error {
+func drawFuzzyNum(dc *gg.Context, num fn.FuzzyNum, low, high float64, rnd *rand.Rand) error {
 	dpDelta := (high - low) / DataPointCount
 	dataPoints := []*dataPoint{}
 
@@ -129,19 +129,19 @@ func drawFuzzyNum(dc *gg.Context, num fn.FuzzyNum, low, high float64) error {
 		dataPoints = append(dataPoints, dp)
 	}
 
-	if err := drawCurve(dc, dataPoints, low, high); err != nil {
+	if err := drawCurve(dc, dataPoints, low, high, rnd); err != nil {
 		return fmt.Errorf("Error drawing point %s", num)
 	}
 
 	return nil
 }
 
-func drawCurve(dc *gg.Context, dataPoints []*dataPoint, low, high float64) error {
+func drawCurve(dc *gg.Context, dataPoints []*dataPoint, low, high float64, rnd *rand.Rand) error {
 	if len(dataPoints) < 2 {
 		return fmt.Errorf("A curve consists of at least 2 points")
 	}
 
-	dc.SetRGBA(rand.Float64(), rand.Float64(), rand.Float64(), CurveAlpha)
+	dc.SetRGBA(rnd.Float64(), rnd.Float64(), rnd.Float64(), CurveAlpha)
 
 	for i := 0; i < len(dataPoints)-1; i++ {
 		currDP := dataPoints[i]